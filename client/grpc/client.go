@@ -3,30 +3,59 @@ package grpcclient
 import (
 	"context"
 	"fmt"
-	"net"
+	"time"
 
+	"QuicksilverDumper/workerpool"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	sdkcodec "github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/query"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	icatypes "github.com/cosmos/ibc-go/v5/modules/apps/27-interchain-accounts/types"
+	ibcClient "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	ibcConnection "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
 	ibcCore "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	solomachine "github.com/cosmos/ibc-go/v5/modules/light-clients/06-solomachine/types"
+	ibctm "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	localhost "github.com/cosmos/ibc-go/v5/modules/light-clients/09-localhost/types"
 	icstypes "github.com/ingenuity-build/quicksilver/x/interchainstaking/types"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type GRPCClient struct {
-	conn          *grpc.ClientConn
-	AuthClient    authtypes.QueryClient
-	IBCClient     ibcCore.QueryClient
-	ICSClient     icstypes.QueryClient
-	StakingClient stakingtypes.QueryClient
+	conn                *failoverConn
+	height              int64
+	concurrency         int
+	retry               RetryPolicy
+	headers             map[string]string
+	AuthClient          authtypes.QueryClient
+	BankClient          banktypes.QueryClient
+	IBCClient           ibcCore.QueryClient
+	IBCConnectionClient ibcConnection.QueryClient
+	IBCClientClient     ibcClient.QueryClient
+	ICSClient           icstypes.QueryClient
+	StakingClient       stakingtypes.QueryClient
+	TmClient            tmservice.ServiceClient
 }
 
 func (g *GRPCClient) GetValidatorDelegations(ctx context.Context, validatorAddr string) (stakingtypes.DelegationResponses, error) {
-	p := paginator[*stakingtypes.QueryValidatorDelegationsRequest, *stakingtypes.QueryValidatorDelegationsResponse, stakingtypes.DelegationResponse]{
+	p := g.validatorDelegationsPaginator(validatorAddr)
+	return p.All(ctx)
+}
+
+func (g *GRPCClient) StreamValidatorDelegations(ctx context.Context, validatorAddr string, fn func(stakingtypes.DelegationResponses) error) error {
+	p := g.validatorDelegationsPaginator(validatorAddr)
+	return p.Each(ctx, func(delegations []stakingtypes.DelegationResponse) error {
+		return fn(delegations)
+	})
+}
+
+func (g *GRPCClient) validatorDelegationsPaginator(validatorAddr string) *paginator[*stakingtypes.QueryValidatorDelegationsRequest, *stakingtypes.QueryValidatorDelegationsResponse, stakingtypes.DelegationResponse] {
+	return &paginator[*stakingtypes.QueryValidatorDelegationsRequest, *stakingtypes.QueryValidatorDelegationsResponse, stakingtypes.DelegationResponse]{
 		req: &stakingtypes.QueryValidatorDelegationsRequest{
 			ValidatorAddr: validatorAddr,
 			Pagination:    &query.PageRequest{Limit: 1000},
@@ -38,10 +67,61 @@ func (g *GRPCClient) GetValidatorDelegations(ctx context.Context, validatorAddr
 			return response.DelegationResponses
 		},
 	}
+}
+
+func (g *GRPCClient) GetValidatorUnbondingDelegations(ctx context.Context, validatorAddr string) ([]stakingtypes.UnbondingDelegation, error) {
+	p := g.validatorUnbondingDelegationsPaginator(validatorAddr)
+	return p.All(ctx)
+}
+
+func (g *GRPCClient) StreamValidatorUnbondingDelegations(ctx context.Context, validatorAddr string, fn func([]stakingtypes.UnbondingDelegation) error) error {
+	p := g.validatorUnbondingDelegationsPaginator(validatorAddr)
+	return p.Each(ctx, fn)
+}
+
+func (g *GRPCClient) validatorUnbondingDelegationsPaginator(validatorAddr string) *paginator[*stakingtypes.QueryValidatorUnbondingDelegationsRequest, *stakingtypes.QueryValidatorUnbondingDelegationsResponse, stakingtypes.UnbondingDelegation] {
+	return &paginator[*stakingtypes.QueryValidatorUnbondingDelegationsRequest, *stakingtypes.QueryValidatorUnbondingDelegationsResponse, stakingtypes.UnbondingDelegation]{
+		req: &stakingtypes.QueryValidatorUnbondingDelegationsRequest{
+			ValidatorAddr: validatorAddr,
+			Pagination:    &query.PageRequest{Limit: 1000},
+		},
+		fn: func(ctx context.Context, request *stakingtypes.QueryValidatorUnbondingDelegationsRequest) (*stakingtypes.QueryValidatorUnbondingDelegationsResponse, error) {
+			return g.StakingClient.ValidatorUnbondingDelegations(ctx, request)
+		},
+		getEntities: func(response *stakingtypes.QueryValidatorUnbondingDelegationsResponse) []stakingtypes.UnbondingDelegation {
+			return response.UnbondingResponses
+		},
+	}
+}
 
+// GetValidatorRedelegations gets the redelegations whose source is the given validator
+func (g *GRPCClient) GetValidatorRedelegations(ctx context.Context, srcValidatorAddr string) (stakingtypes.RedelegationResponses, error) {
+	p := g.validatorRedelegationsPaginator(srcValidatorAddr)
 	return p.All(ctx)
 }
 
+func (g *GRPCClient) StreamValidatorRedelegations(ctx context.Context, srcValidatorAddr string, fn func(stakingtypes.RedelegationResponses) error) error {
+	p := g.validatorRedelegationsPaginator(srcValidatorAddr)
+	return p.Each(ctx, func(redelegations []stakingtypes.RedelegationResponse) error {
+		return fn(redelegations)
+	})
+}
+
+func (g *GRPCClient) validatorRedelegationsPaginator(srcValidatorAddr string) *paginator[redelegationsRequest, *stakingtypes.QueryRedelegationsResponse, stakingtypes.RedelegationResponse] {
+	return &paginator[redelegationsRequest, *stakingtypes.QueryRedelegationsResponse, stakingtypes.RedelegationResponse]{
+		req: redelegationsRequest{&stakingtypes.QueryRedelegationsRequest{
+			SrcValidatorAddr: srcValidatorAddr,
+			Pagination:       &query.PageRequest{Limit: 1000},
+		}},
+		fn: func(ctx context.Context, request redelegationsRequest) (*stakingtypes.QueryRedelegationsResponse, error) {
+			return g.StakingClient.Redelegations(ctx, request.QueryRedelegationsRequest)
+		},
+		getEntities: func(response *stakingtypes.QueryRedelegationsResponse) []stakingtypes.RedelegationResponse {
+			return response.RedelegationResponses
+		},
+	}
+}
+
 func (g *GRPCClient) GetAllValidators(ctx context.Context) ([]stakingtypes.Validator, error) {
 	p := paginator[*stakingtypes.QueryValidatorsRequest, *stakingtypes.QueryValidatorsResponse, stakingtypes.Validator]{
 		req: &stakingtypes.QueryValidatorsRequest{
@@ -58,85 +138,278 @@ func (g *GRPCClient) GetAllValidators(ctx context.Context) ([]stakingtypes.Valid
 	return p.All(ctx)
 }
 
-func NewGRPCClient(nodeUrl string) (*GRPCClient, error) {
+func (g *GRPCClient) GetAllBalances(ctx context.Context, address string) (sdk.Coins, error) {
+	p := paginator[allBalancesRequest, *banktypes.QueryAllBalancesResponse, sdk.Coin]{
+		req: allBalancesRequest{&banktypes.QueryAllBalancesRequest{
+			Address:    address,
+			Pagination: &query.PageRequest{Limit: 1000},
+		}},
+		fn: func(ctx context.Context, request allBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+			return g.BankClient.AllBalances(ctx, request.QueryAllBalancesRequest)
+		},
+		getEntities: func(response *banktypes.QueryAllBalancesResponse) []sdk.Coin {
+			return response.Balances
+		},
+	}
+
+	balances, err := p.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// balances are returned sorted by denom
+	return sdk.Coins(balances), nil
+}
+
+func (g *GRPCClient) GetSpendableBalances(ctx context.Context, address string) (sdk.Coins, error) {
+	p := paginator[spendableBalancesRequest, *banktypes.QuerySpendableBalancesResponse, sdk.Coin]{
+		req: spendableBalancesRequest{&banktypes.QuerySpendableBalancesRequest{
+			Address:    address,
+			Pagination: &query.PageRequest{Limit: 1000},
+		}},
+		fn: func(ctx context.Context, request spendableBalancesRequest) (*banktypes.QuerySpendableBalancesResponse, error) {
+			return g.BankClient.SpendableBalances(ctx, request.QuerySpendableBalancesRequest)
+		},
+		getEntities: func(response *banktypes.QuerySpendableBalancesResponse) []sdk.Coin {
+			return response.Balances
+		},
+	}
+
+	balances, err := p.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// balances are returned sorted by denom
+	return sdk.Coins(balances), nil
+}
+
+func (g *GRPCClient) GetDelegatorDelegations(ctx context.Context, delegatorAddr string) (stakingtypes.DelegationResponses, error) {
+	p := paginator[delegatorDelegationsRequest, *stakingtypes.QueryDelegatorDelegationsResponse, stakingtypes.DelegationResponse]{
+		req: delegatorDelegationsRequest{&stakingtypes.QueryDelegatorDelegationsRequest{
+			DelegatorAddr: delegatorAddr,
+			Pagination:    &query.PageRequest{Limit: 1000},
+		}},
+		fn: func(ctx context.Context, request delegatorDelegationsRequest) (*stakingtypes.QueryDelegatorDelegationsResponse, error) {
+			return g.StakingClient.DelegatorDelegations(ctx, request.QueryDelegatorDelegationsRequest)
+		},
+		getEntities: func(response *stakingtypes.QueryDelegatorDelegationsResponse) []stakingtypes.DelegationResponse {
+			return response.DelegationResponses
+		},
+	}
+
+	return p.All(ctx)
+}
+
+func (g *GRPCClient) GetDelegatorUnbondingDelegations(ctx context.Context, delegatorAddr string) ([]stakingtypes.UnbondingDelegation, error) {
+	p := paginator[delegatorUnbondingDelegationsRequest, *stakingtypes.QueryDelegatorUnbondingDelegationsResponse, stakingtypes.UnbondingDelegation]{
+		req: delegatorUnbondingDelegationsRequest{&stakingtypes.QueryDelegatorUnbondingDelegationsRequest{
+			DelegatorAddr: delegatorAddr,
+			Pagination:    &query.PageRequest{Limit: 1000},
+		}},
+		fn: func(ctx context.Context, request delegatorUnbondingDelegationsRequest) (*stakingtypes.QueryDelegatorUnbondingDelegationsResponse, error) {
+			return g.StakingClient.DelegatorUnbondingDelegations(ctx, request.QueryDelegatorUnbondingDelegationsRequest)
+		},
+		getEntities: func(response *stakingtypes.QueryDelegatorUnbondingDelegationsResponse) []stakingtypes.UnbondingDelegation {
+			return response.UnbondingResponses
+		},
+	}
+
+	return p.All(ctx)
+}
+
+func (g *GRPCClient) StreamDenomOwners(ctx context.Context, denom string, fn func([]*banktypes.DenomOwner) error) error {
+	p := paginator[*banktypes.QueryDenomOwnersRequest, *banktypes.QueryDenomOwnersResponse, *banktypes.DenomOwner]{
+		req: &banktypes.QueryDenomOwnersRequest{
+			Denom:      denom,
+			Pagination: &query.PageRequest{Limit: 1000},
+		},
+		fn: func(ctx context.Context, request *banktypes.QueryDenomOwnersRequest) (*banktypes.QueryDenomOwnersResponse, error) {
+			return g.BankClient.DenomOwners(ctx, request)
+		},
+		getEntities: func(response *banktypes.QueryDenomOwnersResponse) []*banktypes.DenomOwner {
+			return response.DenomOwners
+		},
+	}
+
+	return p.Each(ctx, fn)
+}
+
+func (g *GRPCClient) GetSupplyOf(ctx context.Context, denom string) (sdk.Coin, error) {
+	resp, err := g.BankClient.SupplyOf(ctx, &banktypes.QuerySupplyOfRequest{Denom: denom})
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	return resp.Amount, nil
+}
+
+// GetModuleAccountAddress gets the address of the module account with the given name, e.g. bonded_tokens_pool
+func (g *GRPCClient) GetModuleAccountAddress(ctx context.Context, name string) (string, error) {
+	resp, err := g.AuthClient.ModuleAccountByName(ctx, &authtypes.QueryModuleAccountByNameRequest{Name: name})
+	if err != nil {
+		return "", err
+	}
+	account := &authtypes.ModuleAccount{}
+	if err := account.Unmarshal(resp.Account.GetValue()); err != nil {
+		return "", fmt.Errorf("failed to decode module account %s: %w", name, err)
+	}
+	return account.Address, nil
+}
+
+func (g *GRPCClient) GetBondDenom(ctx context.Context) (string, error) {
+	resp, err := g.StakingClient.Params(ctx, &stakingtypes.QueryParamsRequest{})
+	if err != nil {
+		return "", err
+	}
+	return resp.Params.BondDenom, nil
+}
+
+func (g *GRPCClient) GetAllDenomsMetadata(ctx context.Context) ([]banktypes.Metadata, error) {
+	p := paginator[*banktypes.QueryDenomsMetadataRequest, *banktypes.QueryDenomsMetadataResponse, banktypes.Metadata]{
+		req: &banktypes.QueryDenomsMetadataRequest{
+			Pagination: &query.PageRequest{Limit: 1000},
+		},
+		fn: func(ctx context.Context, request *banktypes.QueryDenomsMetadataRequest) (*banktypes.QueryDenomsMetadataResponse, error) {
+			return g.BankClient.DenomsMetadata(ctx, request)
+		},
+		getEntities: func(response *banktypes.QueryDenomsMetadataResponse) []banktypes.Metadata {
+			return response.Metadatas
+		},
+	}
+
+	return p.All(ctx)
+}
+
+func NewGRPCClient(nodeUrls []string, cfg DialConfig) (*GRPCClient, error) {
+	if len(nodeUrls) == 0 {
+		return nil, fmt.Errorf("no node to connect to")
+	}
+
 	// Create a new InterfaceRegistry
 	interfaceRegistry := codectypes.NewInterfaceRegistry()
 
 	// Register all interfaces needed
 	sdk.RegisterInterfaces(interfaceRegistry)
 	authtypes.RegisterInterfaces(interfaceRegistry)
+	vestingtypes.RegisterInterfaces(interfaceRegistry)
+	icatypes.RegisterInterfaces(interfaceRegistry)
+	ibcClient.RegisterInterfaces(interfaceRegistry)
+	solomachine.RegisterInterfaces(interfaceRegistry)
+	ibctm.RegisterInterfaces(interfaceRegistry)
+	localhost.RegisterInterfaces(interfaceRegistry)
 
 	// Create a new ProtoCodec (used for message encoding/decoding)
 	codec := sdkcodec.NewProtoCodec(interfaceRegistry)
 
-	// Set the node
-	conn, err := grpc.Dial(nodeUrl,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithContextDialer(func(ctx context.Context, url string) (net.Conn, error) {
-			return net.Dial("tcp", url)
-		}),
-		grpc.WithDefaultCallOptions(
-			grpc.ForceCodec(codec.GRPCCodec()),
-		),
-	)
+	resp := &GRPCClient{concurrency: 1, retry: DefaultRetryPolicy(), headers: cfg.Headers}
 
+	creds, err := cfg.TLS.transportCredentials()
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial: %w", err)
+		return nil, err
+	}
+
+	conn := &failoverConn{
+		failsOver:   func(err error) bool { return resp.retry.isRetryable(err) || isMissingHeight(err) },
+		callTimeout: func() time.Duration { return resp.retry.CallTimeout },
+		interceptor: chainInterceptors(resp.heightInterceptor, resp.retryInterceptor),
+	}
+
+	// Set the nodes
+	for _, nodeUrl := range nodeUrls {
+		nodeConn, err := grpc.Dial(nodeUrl,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithDefaultCallOptions(
+				grpc.ForceCodec(codec.GRPCCodec()),
+			),
+			grpc.WithUnaryInterceptor(resp.headersInterceptor),
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial %s: %w", nodeUrl, err)
+		}
+		conn.endpoints = append(conn.endpoints, &endpoint{url: nodeUrl, conn: nodeConn})
 	}
 
 	authCli := authtypes.NewQueryClient(conn)
+	bankCli := banktypes.NewQueryClient(conn)
 	ibcCli := ibcCore.NewQueryClient(conn)
+	ibcConnectionCli := ibcConnection.NewQueryClient(conn)
+	ibcClientCli := ibcClient.NewQueryClient(conn)
 	icsCli := icstypes.NewQueryClient(conn)
 	stakingClient := stakingtypes.NewQueryClient(conn)
+	tmClient := tmservice.NewServiceClient(conn)
 
-	resp := &GRPCClient{
-		conn:          conn,
-		AuthClient:    authCli,
-		IBCClient:     ibcCli,
-		ICSClient:     icsCli,
-		StakingClient: stakingClient,
-	}
+	resp.conn = conn
+	resp.AuthClient = authCli
+	resp.BankClient = bankCli
+	resp.IBCClient = ibcCli
+	resp.IBCConnectionClient = ibcConnectionCli
+	resp.IBCClientClient = ibcClientCli
+	resp.ICSClient = icsCli
+	resp.StakingClient = stakingClient
+	resp.TmClient = tmClient
 
 	return resp, nil
 }
 
+// SetConcurrency bounds the number of parallel queries fanned out by a single method, e.g. per-zone receipts
+func (g *GRPCClient) SetConcurrency(concurrency int) {
+	g.concurrency = concurrency
+}
+
 func (g *GRPCClient) GetAllICSReceipts(ctx context.Context) ([]icstypes.Receipt, error) {
 
-	zonesResp, err := g.ICSClient.ZoneInfos(ctx, &icstypes.QueryZonesInfoRequest{
-		Pagination: &query.PageRequest{CountTotal: true},
-	})
+	zonesPaginator := paginator[*icstypes.QueryZonesInfoRequest, *icstypes.QueryZonesInfoResponse, icstypes.Zone]{
+		req: &icstypes.QueryZonesInfoRequest{
+			Pagination: &query.PageRequest{Limit: 100},
+		},
+		fn: func(ctx context.Context, request *icstypes.QueryZonesInfoRequest) (*icstypes.QueryZonesInfoResponse, error) {
+			return g.ICSClient.ZoneInfos(ctx, request)
+		},
+		getEntities: func(response *icstypes.QueryZonesInfoResponse) []icstypes.Zone {
+			return response.Zones
+		},
+	}
+
+	zones, err := zonesPaginator.All(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get zones: %w", err)
 	}
 
-	chainIds := make([]string, 0, zonesResp.Pagination.Total)
-	for _, zone := range zonesResp.Zones {
+	chainIds := make([]string, 0, len(zones))
+	for _, zone := range zones {
 		chainIds = append(chainIds, zone.ChainId)
 	}
 
 	response := make([]icstypes.Receipt, 0)
-	for _, chainId := range chainIds {
-		req := &icstypes.QueryReceiptsRequest{
-			ChainId:    chainId,
-			Pagination: &query.PageRequest{CountTotal: true, Limit: 1000},
-		}
+	err = workerpool.MapOrdered(ctx, g.concurrency, chainIds,
+		func(ctx context.Context, chainId string) ([]icstypes.Receipt, error) {
+			req := &icstypes.QueryReceiptsRequest{
+				ChainId:    chainId,
+				Pagination: &query.PageRequest{Limit: 1000},
+			}
 
-		p := paginator[*icstypes.QueryReceiptsRequest, *icstypes.QueryReceiptsResponse, icstypes.Receipt]{
-			req: req,
-			fn: func(ctx context.Context, request *icstypes.QueryReceiptsRequest) (*icstypes.QueryReceiptsResponse, error) {
-				return g.ICSClient.Receipts(ctx, request)
-			},
-			getEntities: func(response *icstypes.QueryReceiptsResponse) []icstypes.Receipt {
-				return response.Receipts
-			},
-		}
+			p := paginator[*icstypes.QueryReceiptsRequest, *icstypes.QueryReceiptsResponse, icstypes.Receipt]{
+				req: req,
+				fn: func(ctx context.Context, request *icstypes.QueryReceiptsRequest) (*icstypes.QueryReceiptsResponse, error) {
+					return g.ICSClient.Receipts(ctx, request)
+				},
+				getEntities: func(response *icstypes.QueryReceiptsResponse) []icstypes.Receipt {
+					return response.Receipts
+				},
+			}
 
-		receipts, err := p.All(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get receipts for chain %s: %w", chainId, err)
-		}
-
-		response = append(response, receipts...)
+			receipts, err := p.All(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get receipts for chain %s: %w", chainId, err)
+			}
+			return receipts, nil
+		},
+		func(receipts []icstypes.Receipt) error {
+			response = append(response, receipts...)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	return response, nil
@@ -145,7 +418,7 @@ func (g *GRPCClient) GetAllICSReceipts(ctx context.Context) ([]icstypes.Receipt,
 func (g *GRPCClient) GetAllIBCChannels(ctx context.Context) ([]*ibcCore.IdentifiedChannel, error) {
 	p := paginator[*ibcCore.QueryChannelsRequest, *ibcCore.QueryChannelsResponse, *ibcCore.IdentifiedChannel]{
 		req: &ibcCore.QueryChannelsRequest{
-			Pagination: &query.PageRequest{CountTotal: true, Limit: 1000},
+			Pagination: &query.PageRequest{Limit: 1000},
 		},
 		fn: func(ctx context.Context, request *ibcCore.QueryChannelsRequest) (*ibcCore.QueryChannelsResponse, error) {
 			return g.IBCClient.Channels(ctx, request)
@@ -158,15 +431,53 @@ func (g *GRPCClient) GetAllIBCChannels(ctx context.Context) ([]*ibcCore.Identifi
 	return p.All(ctx)
 }
 
+func (g *GRPCClient) GetIBCConnection(ctx context.Context, connectionId string) (*ibcConnection.ConnectionEnd, error) {
+	resp, err := g.IBCConnectionClient.Connection(ctx, &ibcConnection.QueryConnectionRequest{ConnectionId: connectionId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection %s: %w", connectionId, err)
+	}
+	return resp.Connection, nil
+}
+
+func (g *GRPCClient) GetIBCClientState(ctx context.Context, clientId string) (*codectypes.Any, error) {
+	resp, err := g.IBCClientClient.ClientState(ctx, &ibcClient.QueryClientStateRequest{ClientId: clientId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client state %s: %w", clientId, err)
+	}
+	return resp.ClientState, nil
+}
+
+func (g *GRPCClient) GetIBCClientStatus(ctx context.Context, clientId string) (string, error) {
+	resp, err := g.IBCClientClient.ClientStatus(ctx, &ibcClient.QueryClientStatusRequest{ClientId: clientId})
+	if err != nil {
+		return "", fmt.Errorf("failed to get client status %s: %w", clientId, err)
+	}
+	return resp.Status, nil
+}
+
 func (g *GRPCClient) GetAllAccounts(ctx context.Context) ([]*codectypes.Any, error) {
+	p := g.accountsPaginator()
+	resp, err := p.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *GRPCClient) StreamAllAccounts(ctx context.Context, fn func([]*codectypes.Any) error) error {
+	p := g.accountsPaginator()
+	return p.Each(ctx, fn)
+}
+
+func (g *GRPCClient) accountsPaginator() *paginator[*authtypes.QueryAccountsRequest, *authtypes.QueryAccountsResponse, *codectypes.Any] {
 	req := &authtypes.QueryAccountsRequest{
 		Pagination: &query.PageRequest{
-			CountTotal: true,
-			Limit:      500,
+			Limit: 500,
 		},
 	}
 
-	p := paginator[*authtypes.QueryAccountsRequest, *authtypes.QueryAccountsResponse, *codectypes.Any]{
+	return &paginator[*authtypes.QueryAccountsRequest, *authtypes.QueryAccountsResponse, *codectypes.Any]{
 		req: req,
 		fn: func(ctx context.Context, request *authtypes.QueryAccountsRequest) (*authtypes.QueryAccountsResponse, error) {
 			return g.AuthClient.Accounts(ctx, request)
@@ -175,11 +486,4 @@ func (g *GRPCClient) GetAllAccounts(ctx context.Context) ([]*codectypes.Any, err
 			return response.Accounts
 		},
 	}
-
-	resp, err := p.All(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
 }
@@ -0,0 +1,79 @@
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// DialConfig describes how to connect and authenticate to a node
+type DialConfig struct {
+	TLS TLSConfig
+	// Headers are sent as metadata with every call, e.g. an API key required by a node provider
+	Headers map[string]string
+}
+
+type TLSConfig struct {
+	// Enabled turns on TLS, it is implied by any of the other fields
+	Enabled bool
+	// CAFile is a PEM bundle used instead of the system roots to verify the node
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for mutual TLS
+	CertFile string
+	KeyFile  string
+	// SkipVerify disables verification of the node certificate
+	SkipVerify bool
+}
+
+func (c TLSConfig) enabled() bool {
+	return c.Enabled || c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.SkipVerify
+}
+
+// transportCredentials builds insecure credentials, or TLS ones verified by the system roots unless configured otherwise
+func (c TLSConfig) transportCredentials() (credentials.TransportCredentials, error) {
+	if !c.enabled() {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.SkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// headersInterceptor attaches the configured headers to every outgoing call
+func (g *GRPCClient) headersInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	for key, value := range g.headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
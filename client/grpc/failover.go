@@ -0,0 +1,241 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var _ grpc.ClientConnInterface = &failoverConn{}
+
+type endpoint struct {
+	url  string
+	conn *grpc.ClientConn
+	// latestHeight is the latest height of the endpoint at the last health check, 0 when the check failed
+	latestHeight int64
+}
+
+// failoverConn sends every call to the active endpoint and moves on to the next one when the call fails with a transient error,
+// including the timeout of the attempt on that endpoint, or when the endpoint does not have the state at the pinned height.
+// When every endpoint failed the next one becomes active.
+// The interceptor wraps the whole failover, so a retried call starts over from the endpoint that is active by then.
+type failoverConn struct {
+	mu          sync.Mutex
+	endpoints   []*endpoint
+	active      int
+	failsOver   func(error) bool
+	callTimeout func() time.Duration
+	interceptor grpc.UnaryClientInterceptor
+}
+
+func (f *failoverConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	if f.interceptor == nil {
+		return f.invoke(ctx, method, args, reply, nil, opts...)
+	}
+	return f.interceptor(ctx, method, args, reply, nil, f.invoke, opts...)
+}
+
+func (f *failoverConn) invoke(ctx context.Context, method string, args, reply interface{}, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+	endpoints, start := f.snapshot()
+
+	var err error
+	for i := range endpoints {
+		idx := (start + i) % len(endpoints)
+		err = f.invokeEndpoint(ctx, endpoints[idx], method, args, reply, opts...)
+		if err == nil {
+			f.setActive(start, idx)
+			return nil
+		}
+		if ctx.Err() != nil || !f.failsOver(err) {
+			return err
+		}
+	}
+	f.setActive(start, (start+1)%len(endpoints))
+	return err
+}
+
+// invokeEndpoint makes a single attempt on the endpoint, bounded by the call timeout
+func (f *failoverConn) invokeEndpoint(ctx context.Context, e *endpoint, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	if f.callTimeout != nil {
+		if timeout := f.callTimeout(); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+	return e.conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+func (f *failoverConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	endpoints, active := f.snapshot()
+	return endpoints[active].conn.NewStream(ctx, desc, method, opts...)
+}
+
+func (f *failoverConn) snapshot() ([]*endpoint, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.endpoints, f.active
+}
+
+// setActive switches to the endpoint that served a call, unless another call already switched away from the one it started with
+func (f *failoverConn) setActive(from, to int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active == from {
+		f.active = to
+	}
+}
+
+// EndpointHealth is the result of a health check of a single endpoint
+type EndpointHealth struct {
+	URL          string
+	LatestHeight int64
+	Syncing      bool
+	Err          error
+}
+
+func (h EndpointHealth) healthy(minHeight int64) bool {
+	return h.Err == nil && !h.Syncing && h.LatestHeight >= minHeight
+}
+
+func (h EndpointHealth) String() string {
+	if h.Err != nil {
+		return fmt.Sprintf("%s: unavailable: %v", h.URL, h.Err)
+	}
+	return fmt.Sprintf("%s: height %d, syncing %t", h.URL, h.LatestHeight, h.Syncing)
+}
+
+// CheckEndpoints queries the latest height and sync status of every endpoint and orders the endpoints for failover:
+// synced endpoints that have the pinned height (if any) first, highest latest height first. When a height is pinned,
+// the endpoints also have to serve a query at that height, as pruned nodes reach past it but no longer have its state.
+// The best endpoint becomes the active one. An error is returned when no endpoint is healthy.
+// The timeout bounds the check of every endpoint, 0 means no timeout.
+func (g *GRPCClient) CheckEndpoints(ctx context.Context, timeout time.Duration) ([]EndpointHealth, error) {
+	endpoints, _ := g.conn.snapshot()
+
+	health := make([]EndpointHealth, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			health[i] = checkEndpoint(ctx, e, timeout, g.height)
+		}(i, e)
+	}
+	wg.Wait()
+	for i, e := range endpoints {
+		e.latestHeight = 0
+		if health[i].Err == nil {
+			e.latestHeight = health[i].LatestHeight
+		}
+	}
+
+	order := make([]int, len(endpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		hi, hj := health[order[i]], health[order[j]]
+		if hi.healthy(g.height) != hj.healthy(g.height) {
+			return hi.healthy(g.height)
+		}
+		return hi.LatestHeight > hj.LatestHeight
+	})
+
+	sortedEndpoints := make([]*endpoint, len(endpoints))
+	sortedHealth := make([]EndpointHealth, len(endpoints))
+	for i, idx := range order {
+		sortedEndpoints[i] = endpoints[idx]
+		sortedHealth[i] = health[idx]
+	}
+
+	g.conn.mu.Lock()
+	g.conn.endpoints = sortedEndpoints
+	g.conn.active = 0
+	g.conn.mu.Unlock()
+
+	if !sortedHealth[0].healthy(g.height) {
+		return sortedHealth, fmt.Errorf("no healthy endpoint among %d", len(endpoints))
+	}
+	return sortedHealth, nil
+}
+
+// DropEndpointsBelow removes from the failover the endpoints whose latest height at the last health check is below height,
+// or whose check failed, as they would fail queries pinned to it. The active endpoint is always kept. It returns the URLs of the dropped endpoints.
+func (g *GRPCClient) DropEndpointsBelow(height int64) []string {
+	g.conn.mu.Lock()
+	defer g.conn.mu.Unlock()
+
+	active := g.conn.endpoints[g.conn.active]
+	var kept []*endpoint
+	var dropped []string
+	for _, e := range g.conn.endpoints {
+		if e != active && e.latestHeight < height {
+			dropped = append(dropped, e.url)
+			continue
+		}
+		if e == active {
+			g.conn.active = len(kept)
+		}
+		kept = append(kept, e)
+	}
+	g.conn.endpoints = kept
+	return dropped
+}
+
+func checkEndpoint(ctx context.Context, e *endpoint, timeout time.Duration, height int64) EndpointHealth {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	h := EndpointHealth{URL: e.url}
+	// the check bypasses the client interceptors, so it is neither retried nor pinned to a height
+	tmClient := tmservice.NewServiceClient(e.conn)
+
+	syncing, err := tmClient.GetSyncing(ctx, &tmservice.GetSyncingRequest{})
+	if err != nil {
+		h.Err = fmt.Errorf("failed to get sync status: %w", err)
+		return h
+	}
+	h.Syncing = syncing.Syncing
+
+	h.LatestHeight, err = latestHeight(ctx, tmClient)
+	if err != nil {
+		h.Err = err
+		return h
+	}
+
+	if height > 0 && h.LatestHeight >= height {
+		// a cheap query pinned to the height fails on nodes pruned past it
+		pinnedCtx := metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+		if _, err := authtypes.NewQueryClient(e.conn).Params(pinnedCtx, &authtypes.QueryParamsRequest{}); err != nil {
+			h.Err = fmt.Errorf("failed to query state at height %d: %w", height, err)
+		}
+	}
+	return h
+}
+
+// chainInterceptors composes interceptors into one, the first one being the outermost
+func chainInterceptors(interceptors ...grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		chained := invoker
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return interceptor(ctx, method, req, reply, cc, next, opts...)
+			}
+		}
+		return chained(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,323 @@
+package grpcclient
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/codec"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	syncingMethod       = "/cosmos.base.tendermint.v1beta1.Service/GetSyncing"
+	latestBlockMethod   = "/cosmos.base.tendermint.v1beta1.Service/GetLatestBlock"
+	authParamsMethod    = "/cosmos.auth.v1beta1.Query/Params"
+	stakingParamsMethod = "/cosmos.staking.v1beta1.Query/Params"
+)
+
+// rawFrame carries already encoded messages through the fake node, which has no generated services
+type rawFrame struct {
+	data []byte
+}
+
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) { return v.(*rawFrame).data, nil }
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	v.(*rawFrame).data = data
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
+
+// fakeNode is an in-process gRPC server answering the queries of the health check and GetBondDenom
+type fakeNode struct {
+	addr         string
+	latestHeight int64
+	// fail returns the error of a call, if any, given its method and pinned height (0 when not pinned)
+	fail func(ctx context.Context, method string, height int64) error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func startFakeNode(t *testing.T, latestHeight int64, fail func(ctx context.Context, method string, height int64) error) *fakeNode {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &fakeNode{addr: lis.Addr().String(), latestHeight: latestHeight, fail: fail}
+	srv := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(n.serve))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return n
+}
+
+func (n *fakeNode) serve(_ interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	if err := stream.RecvMsg(&rawFrame{}); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	n.calls = append(n.calls, method)
+	n.mu.Unlock()
+
+	var height int64
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if values := md.Get(grpctypes.GRPCBlockHeightHeader); len(values) > 0 {
+		height, _ = strconv.ParseInt(values[0], 10, 64)
+	}
+	if n.fail != nil {
+		if err := n.fail(stream.Context(), method, height); err != nil {
+			return err
+		}
+	}
+
+	var resp codec.ProtoMarshaler
+	switch method {
+	case syncingMethod:
+		resp = &tmservice.GetSyncingResponse{}
+	case latestBlockMethod:
+		resp = &tmservice.GetLatestBlockResponse{SdkBlock: &tmservice.Block{Header: tmservice.Header{Height: n.latestHeight}}}
+	case authParamsMethod:
+		resp = &authtypes.QueryParamsResponse{}
+	case stakingParamsMethod:
+		resp = &stakingtypes.QueryParamsResponse{Params: stakingtypes.Params{BondDenom: "uqck"}}
+	default:
+		return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+	data, err := resp.Marshal()
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(&rawFrame{data: data})
+}
+
+// bondDenomCalls counts the GetBondDenom calls the node received
+func (n *fakeNode) bondDenomCalls() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	count := 0
+	for _, method := range n.calls {
+		if method == stakingParamsMethod {
+			count++
+		}
+	}
+	return count
+}
+
+func failBondDenom(err error) func(context.Context, string, int64) error {
+	return func(_ context.Context, method string, _ int64) error {
+		if method == stakingParamsMethod {
+			return err
+		}
+		return nil
+	}
+}
+
+// newTestClient connects to the nodes in order, with a single attempt per call unless the test sets another policy
+func newTestClient(t *testing.T, nodes ...*fakeNode) *GRPCClient {
+	t.Helper()
+	urls := make([]string, len(nodes))
+	for i, n := range nodes {
+		urls[i] = n.addr
+	}
+	client, err := NewGRPCClient(urls, DialConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 1
+	policy.CallTimeout = 5 * time.Second
+	client.SetRetryPolicy(policy)
+	return client
+}
+
+func (g *GRPCClient) activeURL() string {
+	endpoints, active := g.conn.snapshot()
+	return endpoints[active].url
+}
+
+func TestFailoverMovesToNextEndpointOnTransientError(t *testing.T) {
+	down := startFakeNode(t, 100, failBondDenom(status.Error(codes.Unavailable, "down")))
+	up := startFakeNode(t, 100, nil)
+	unused := startFakeNode(t, 100, nil)
+	client := newTestClient(t, down, up, unused)
+
+	for i := 0; i < 2; i++ {
+		denom, err := client.GetBondDenom(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if denom != "uqck" {
+			t.Fatalf("got bond denom %q, want uqck", denom)
+		}
+	}
+
+	// the endpoint that served the first call stays active, so the failing one is tried only once
+	if got := [3]int{down.bondDenomCalls(), up.bondDenomCalls(), unused.bondDenomCalls()}; got != [3]int{1, 2, 0} {
+		t.Errorf("got calls per endpoint %v, want [1 2 0]", got)
+	}
+	if client.activeURL() != up.addr {
+		t.Errorf("active endpoint is %s, want %s", client.activeURL(), up.addr)
+	}
+}
+
+func TestFailoverStopsOnNonTransientError(t *testing.T) {
+	notFound := startFakeNode(t, 100, failBondDenom(status.Error(codes.NotFound, "not found")))
+	up := startFakeNode(t, 100, nil)
+	client := newTestClient(t, notFound, up)
+
+	_, err := client.GetBondDenom(context.Background())
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("got error %v, want NotFound", err)
+	}
+	if up.bondDenomCalls() != 0 {
+		t.Errorf("next endpoint got %d calls, want none", up.bondDenomCalls())
+	}
+	if client.activeURL() != notFound.addr {
+		t.Errorf("active endpoint is %s, want %s", client.activeURL(), notFound.addr)
+	}
+}
+
+func TestFailoverOnMissingHeight(t *testing.T) {
+	pruned := startFakeNode(t, 100, failBondDenom(status.Error(codes.InvalidArgument,
+		"failed to load state at height 50; version does not exist (latest height: 100): invalid request")))
+	archive := startFakeNode(t, 100, nil)
+	client := newTestClient(t, pruned, archive)
+	client.SetHeight(50)
+
+	if _, err := client.GetBondDenom(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if archive.bondDenomCalls() != 1 {
+		t.Errorf("archive endpoint got %d calls, want 1", archive.bondDenomCalls())
+	}
+}
+
+func TestFailoverRotatesAfterEveryEndpointFailed(t *testing.T) {
+	unavailable := failBondDenom(status.Error(codes.Unavailable, "down"))
+	nodes := []*fakeNode{startFakeNode(t, 100, unavailable), startFakeNode(t, 100, unavailable), startFakeNode(t, 100, unavailable)}
+	client := newTestClient(t, nodes...)
+
+	_, err := client.GetBondDenom(context.Background())
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got error %v, want Unavailable", err)
+	}
+	for i, n := range nodes {
+		if n.bondDenomCalls() != 1 {
+			t.Errorf("endpoint %d got %d calls, want 1", i, n.bondDenomCalls())
+		}
+	}
+	if client.activeURL() != nodes[1].addr {
+		t.Errorf("active endpoint is %s, want the second one %s", client.activeURL(), nodes[1].addr)
+	}
+}
+
+func TestFailoverTimeoutIsPerEndpoint(t *testing.T) {
+	hanging := startFakeNode(t, 100, func(ctx context.Context, method string, _ int64) error {
+		if method == stakingParamsMethod {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	up := startFakeNode(t, 100, nil)
+	client := newTestClient(t, hanging, up)
+	policy := client.retry
+	policy.CallTimeout = 100 * time.Millisecond
+	client.SetRetryPolicy(policy)
+
+	start := time.Now()
+	if _, err := client.GetBondDenom(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("call took %s, want the hanging endpoint to time out after %s", elapsed, policy.CallTimeout)
+	}
+	if up.bondDenomCalls() != 1 {
+		t.Errorf("next endpoint got %d calls, want 1", up.bondDenomCalls())
+	}
+}
+
+func TestSetActiveKeepsConcurrentSwitch(t *testing.T) {
+	f := &failoverConn{endpoints: make([]*endpoint, 3)}
+
+	f.setActive(0, 2)
+	if f.active != 2 {
+		t.Fatalf("active is %d, want 2", f.active)
+	}
+	// a call that started on endpoint 0 must not undo the switch made by another call
+	f.setActive(0, 1)
+	if f.active != 2 {
+		t.Errorf("active is %d, want 2", f.active)
+	}
+}
+
+func TestCheckEndpointsProbesPinnedHeight(t *testing.T) {
+	pruned := startFakeNode(t, 120, func(_ context.Context, method string, height int64) error {
+		if height > 0 && height < 100 {
+			return status.Errorf(codes.InvalidArgument, "failed to load state at height %d; version does not exist", height)
+		}
+		return nil
+	})
+	archive := startFakeNode(t, 110, nil)
+	lagging := startFakeNode(t, 40, nil)
+	client := newTestClient(t, pruned, archive, lagging)
+	client.SetHeight(50)
+
+	health, err := client.CheckEndpoints(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	for _, h := range health {
+		urls = append(urls, h.URL)
+	}
+	// the pruned endpoint is unhealthy despite the highest latest height, it is ordered by height with the lagging one
+	if want := []string{archive.addr, pruned.addr, lagging.addr}; !reflect.DeepEqual(urls, want) {
+		t.Fatalf("got endpoints %v, want %v", urls, want)
+	}
+	if health[1].Err == nil {
+		t.Errorf("pruned endpoint is healthy, want an error")
+	}
+
+	dropped := client.DropEndpointsBelow(50)
+	if want := []string{pruned.addr, lagging.addr}; !reflect.DeepEqual(dropped, want) {
+		t.Errorf("dropped %v, want %v", dropped, want)
+	}
+}
+
+func TestCheckEndpointsFailsWithoutHealthyEndpoint(t *testing.T) {
+	syncingFails := func(_ context.Context, method string, _ int64) error {
+		if method == syncingMethod {
+			return status.Error(codes.Unavailable, "down")
+		}
+		return nil
+	}
+	client := newTestClient(t, startFakeNode(t, 100, syncingFails), startFakeNode(t, 100, syncingFails))
+
+	health, err := client.CheckEndpoints(context.Background(), time.Second)
+	if err == nil {
+		t.Fatal("got no error, want one")
+	}
+	for _, h := range health {
+		if h.Err == nil {
+			t.Errorf("endpoint %s is healthy, want an error", h.URL)
+		}
+	}
+}
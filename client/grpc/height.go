@@ -0,0 +1,57 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// SetHeight pins all subsequent queries to the given block height, 0 means latest
+func (g *GRPCClient) SetHeight(height int64) {
+	g.height = height
+}
+
+// Height returns the block height queries are pinned to, 0 means latest
+func (g *GRPCClient) Height() int64 {
+	return g.height
+}
+
+// GetLatestHeight gets the latest block height known by the node
+func (g *GRPCClient) GetLatestHeight(ctx context.Context) (int64, error) {
+	return latestHeight(ctx, g.TmClient)
+}
+
+func latestHeight(ctx context.Context, tmClient tmservice.ServiceClient) (int64, error) {
+	resp, err := tmClient.GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if resp.SdkBlock != nil {
+		return resp.SdkBlock.Header.Height, nil
+	}
+	if resp.Block != nil {
+		return resp.Block.Header.Height, nil
+	}
+	return 0, fmt.Errorf("latest block response has no block")
+}
+
+// heightInterceptor attaches the pinned block height to every outgoing call
+func (g *GRPCClient) heightInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if g.height > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(g.height, 10))
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// isMissingHeight tells whether a call failed because the node does not have the state at the pinned height,
+// e.g. a node pruned past it. Another node may still have it, so the call fails over but is not retried.
+func isMissingHeight(err error) bool {
+	return strings.Contains(status.Convert(err).Message(), "failed to load state at height")
+}
@@ -22,34 +22,64 @@ type paginator[request PaginationReq, response PaginationResp[entity], entity an
 
 // TODO: Benchmark to find optimal batch size
 func (p *paginator[request, response, entity]) All(ctx context.Context) ([]entity, error) {
-	pagesCount := 1
-	resp, err := p.fn(ctx, p.req)
+	var result []entity
+	err := p.Each(ctx, func(entities []entity) error {
+		result = append(result, entities...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get page %d: %w", pagesCount, err)
+		return nil, err
 	}
+	return result, nil
+}
 
-	batchSize := p.req.GetPagination().Limit
-	var totalCount uint64
-	if resp.GetPagination() != nil {
-		totalCount = resp.GetPagination().Total
+// Each calls fn with the entities of every page as soon as the page arrives, stopping at the first error
+func (p *paginator[request, response, entity]) Each(ctx context.Context, fn func([]entity) error) error {
+	for page := 1; ; page++ {
+		resp, err := p.fn(ctx, p.req)
+		if err != nil {
+			return fmt.Errorf("failed to get page %d: %w", page, err)
+		}
+
+		entities := p.getEntities(resp)
+		if len(entities) > 0 {
+			if err := fn(entities); err != nil {
+				return err
+			}
+		}
+
+		if !nextPage(p.req.GetPagination(), resp.GetPagination(), len(entities)) {
+			return nil
+		}
 	}
+}
 
-	entities := p.getEntities(resp)
-	usedCount := len(entities)
+// nextPage moves pageReq to the page following the received one and reports whether there is such a page.
+// It follows NextKey when the server returns one and falls back to offsets otherwise.
+// A response without pagination comes from a server ignoring it, like the Quicksilver receipts query, and is the only page.
+func nextPage(pageReq *query.PageRequest, pageResp *query.PageResponse, received int) bool {
+	if received == 0 || pageResp == nil {
+		return false
+	}
 
-	result := make([]entity, 0, totalCount)
-	result = append(result, entities...)
+	if len(pageResp.NextKey) > 0 {
+		pageReq.Key = pageResp.NextKey
+		pageReq.Offset = 0
+		return true
+	}
+	if len(pageReq.Key) > 0 {
+		// key based pagination reached the last page
+		return false
+	}
 
-	for usedCount < int(totalCount) {
-		pagesCount++
-		p.req.GetPagination().Offset += batchSize
-		resp, err = p.fn(ctx, p.req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get page %d: %w", pagesCount, err)
-		}
-		entities = p.getEntities(resp)
-		usedCount += len(entities)
-		result = append(result, entities...)
+	// the server does not return keys, page by offset until a short page or the reported total.
+	// A page over the limit means the limit was ignored, paging again would fetch the same entities.
+	if pageReq.Limit > 0 && uint64(received) != pageReq.Limit {
+		return false
 	}
-	return result, nil
+	pageReq.Offset += uint64(received)
+	if pageResp.Total > 0 && pageReq.Offset >= pageResp.Total {
+		return false
+	}
+	return true
 }
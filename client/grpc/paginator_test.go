@@ -0,0 +1,134 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+type fakeRequest struct {
+	Pagination *query.PageRequest
+}
+
+func (r *fakeRequest) GetPagination() *query.PageRequest { return r.Pagination }
+
+type fakeResponse struct {
+	Items      []int
+	Pagination *query.PageResponse
+}
+
+func (r *fakeResponse) GetPagination() *query.PageResponse { return r.Pagination }
+
+type fakeMode int
+
+const (
+	keyPaging fakeMode = iota
+	offsetPaging
+	offsetPagingWithTotal
+	unpaginated
+)
+
+// fakeServer serves items the way the different cosmos query handlers do
+type fakeServer struct {
+	items []int
+	mode  fakeMode
+	calls int
+}
+
+func (s *fakeServer) query(_ context.Context, req *fakeRequest) (*fakeResponse, error) {
+	s.calls++
+	if s.calls > 100 {
+		panic("paginator does not stop")
+	}
+	if s.mode == unpaginated {
+		return &fakeResponse{Items: s.items}, nil
+	}
+
+	start := int(req.Pagination.Offset)
+	if len(req.Pagination.Key) > 0 {
+		start = int(binary.BigEndian.Uint64(req.Pagination.Key))
+	}
+	end := start + int(req.Pagination.Limit)
+	if end > len(s.items) {
+		end = len(s.items)
+	}
+	if start > end {
+		start = end
+	}
+
+	resp := &fakeResponse{Items: s.items[start:end], Pagination: &query.PageResponse{}}
+	switch s.mode {
+	case keyPaging:
+		if end < len(s.items) {
+			resp.Pagination.NextKey = binary.BigEndian.AppendUint64(nil, uint64(end))
+		}
+	case offsetPagingWithTotal:
+		resp.Pagination.Total = uint64(len(s.items))
+	}
+	return resp, nil
+}
+
+func newFakePaginator(server *fakeServer, limit uint64) *paginator[*fakeRequest, *fakeResponse, int] {
+	return &paginator[*fakeRequest, *fakeResponse, int]{
+		req: &fakeRequest{Pagination: &query.PageRequest{Limit: limit}},
+		fn:  server.query,
+		getEntities: func(resp *fakeResponse) []int {
+			return resp.Items
+		},
+	}
+}
+
+func fakeItems(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
+
+func TestPaginatorAll(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     int
+		mode      fakeMode
+		limit     uint64
+		wantCalls int
+	}{
+		{name: "key paging", items: 25, mode: keyPaging, limit: 10, wantCalls: 3},
+		{name: "key paging full last page", items: 20, mode: keyPaging, limit: 10, wantCalls: 2},
+		{name: "offset paging", items: 25, mode: offsetPaging, limit: 10, wantCalls: 3},
+		{name: "offset paging full last page", items: 20, mode: offsetPaging, limit: 10, wantCalls: 3},
+		{name: "offset paging with total", items: 20, mode: offsetPagingWithTotal, limit: 10, wantCalls: 2},
+		{name: "short first page", items: 5, mode: offsetPaging, limit: 10, wantCalls: 1},
+		{name: "empty first page", items: 0, mode: keyPaging, limit: 10, wantCalls: 1},
+		{name: "empty first page by offset", items: 0, mode: offsetPaging, limit: 10, wantCalls: 1},
+		{name: "unpaginated", items: 1200, mode: unpaginated, limit: 1000, wantCalls: 1},
+		{name: "unpaginated exactly limit", items: 1000, mode: unpaginated, limit: 1000, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeServer{items: fakeItems(tt.items), mode: tt.mode}
+			got, err := newFakePaginator(server, tt.limit).All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fakeItems(tt.items); len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Errorf("got %d items, want %d in order", len(got), len(want))
+			}
+			if server.calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", server.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestNextPageIgnoredLimit(t *testing.T) {
+	// a server returning more than the limit with an empty page response ignores the limit
+	pageReq := &query.PageRequest{Limit: 1000}
+	if nextPage(pageReq, &query.PageResponse{}, 1200) {
+		t.Error("expected no next page when more entities than the limit are received")
+	}
+}
@@ -0,0 +1,49 @@
+package grpcclient
+
+import (
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// Some requests are generated without getters, the wrappers below expose their pagination to the paginator
+
+type allBalancesRequest struct {
+	*banktypes.QueryAllBalancesRequest
+}
+
+func (r allBalancesRequest) GetPagination() *query.PageRequest {
+	return r.Pagination
+}
+
+type spendableBalancesRequest struct {
+	*banktypes.QuerySpendableBalancesRequest
+}
+
+func (r spendableBalancesRequest) GetPagination() *query.PageRequest {
+	return r.Pagination
+}
+
+type delegatorDelegationsRequest struct {
+	*stakingtypes.QueryDelegatorDelegationsRequest
+}
+
+func (r delegatorDelegationsRequest) GetPagination() *query.PageRequest {
+	return r.Pagination
+}
+
+type delegatorUnbondingDelegationsRequest struct {
+	*stakingtypes.QueryDelegatorUnbondingDelegationsRequest
+}
+
+func (r delegatorUnbondingDelegationsRequest) GetPagination() *query.PageRequest {
+	return r.Pagination
+}
+
+type redelegationsRequest struct {
+	*stakingtypes.QueryRedelegationsRequest
+}
+
+func (r redelegationsRequest) GetPagination() *query.PageRequest {
+	return r.Pagination
+}
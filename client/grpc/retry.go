@@ -0,0 +1,86 @@
+package grpcclient
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy describes how failed calls are retried.
+// Every call, including every page of a paginated query, is retried on its own,
+// so a failing page is re-requested without restarting the whole query.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call, values below 1 mean a single attempt
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the delay before the first retry, it doubles with every retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// CallTimeout bounds every single attempt on an endpoint, 0 means no timeout.
+	// An attempt timing out fails over to the next endpoint like any transient error.
+	CallTimeout time.Duration
+	// RetryableCodes are the status codes considered transient
+	RetryableCodes []codes.Code
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		CallTimeout:    time.Minute,
+		RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded},
+	}
+}
+
+// SetRetryPolicy replaces the policy used to retry failed calls
+func (g *GRPCClient) SetRetryPolicy(policy RetryPolicy) {
+	g.retry = policy
+}
+
+// retryInterceptor retries transient failures with exponential backoff and full jitter
+func (g *GRPCClient) retryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	policy := g.retry
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(policy.backoff(attempt)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (p RetryPolicy) isRetryable(err error) bool {
+	code := status.Code(err)
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns a random delay up to InitialBackoff*2^(attempt-1), capped by MaxBackoff
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	limit := p.InitialBackoff
+	for i := 1; i < attempt && limit < p.MaxBackoff; i++ {
+		limit *= 2
+	}
+	if p.MaxBackoff > 0 && limit > p.MaxBackoff {
+		limit = p.MaxBackoff
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(limit)))
+}
@@ -0,0 +1,114 @@
+package grpcclient
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	node := startFakeNode(t, 100, failBondDenom(status.Error(codes.Unavailable, "down")))
+	client := newTestClient(t, node)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		RetryableCodes: []codes.Code{codes.Unavailable},
+	})
+
+	_, err := client.GetBondDenom(context.Background())
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("got error %v, want Unavailable", err)
+	}
+	if node.bondDenomCalls() != 3 {
+		t.Errorf("got %d attempts, want 3", node.bondDenomCalls())
+	}
+}
+
+func TestRetrySucceedsAfterTransientErrors(t *testing.T) {
+	var failures int32 = 2
+	node := startFakeNode(t, 100, func(_ context.Context, method string, _ int64) error {
+		if method == stakingParamsMethod && atomic.AddInt32(&failures, -1) >= 0 {
+			return status.Error(codes.ResourceExhausted, "rate limited")
+		}
+		return nil
+	})
+	client := newTestClient(t, node)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		RetryableCodes: []codes.Code{codes.ResourceExhausted},
+	})
+
+	if _, err := client.GetBondDenom(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if node.bondDenomCalls() != 3 {
+		t.Errorf("got %d attempts, want 3", node.bondDenomCalls())
+	}
+}
+
+func TestRetrySkipsNonTransientErrors(t *testing.T) {
+	node := startFakeNode(t, 100, failBondDenom(status.Error(codes.InvalidArgument, "invalid")))
+	client := newTestClient(t, node)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, RetryableCodes: []codes.Code{codes.Unavailable}})
+
+	if _, err := client.GetBondDenom(context.Background()); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("got error %v, want InvalidArgument", err)
+	}
+	if node.bondDenomCalls() != 1 {
+		t.Errorf("got %d attempts, want 1", node.bondDenomCalls())
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	node := startFakeNode(t, 100, failBondDenom(status.Error(codes.Unavailable, "down")))
+	client := newTestClient(t, node)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxAttempts:    100,
+		InitialBackoff: time.Hour,
+		MaxBackoff:     time.Hour,
+		RetryableCodes: []codes.Code{codes.Unavailable},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetBondDenom(ctx); err == nil {
+		t.Fatal("got no error, want one")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("call took %s, want it to stop when the context is done", elapsed)
+	}
+}
+
+func TestBackoffLimits(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		attempt int
+		limit   time.Duration
+	}{
+		{attempt: 1, limit: 100 * time.Millisecond},
+		{attempt: 2, limit: 200 * time.Millisecond},
+		{attempt: 4, limit: 800 * time.Millisecond},
+		{attempt: 5, limit: time.Second},
+		{attempt: 50, limit: time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 1000; i++ {
+			if backoff := policy.backoff(tt.attempt); backoff < 0 || backoff >= tt.limit {
+				t.Fatalf("backoff of attempt %d is %s, want within [0, %s)", tt.attempt, backoff, tt.limit)
+			}
+		}
+	}
+
+	if backoff := (RetryPolicy{}).backoff(3); backoff != 0 {
+		t.Errorf("backoff without initial backoff is %s, want 0", backoff)
+	}
+}
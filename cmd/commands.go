@@ -2,10 +2,18 @@ package main
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	grpcclient "QuicksilverDumper/client/grpc"
 	"QuicksilverDumper/output"
-	csvoutput "QuicksilverDumper/output/csv"
+	_ "QuicksilverDumper/output/csv"
+	_ "QuicksilverDumper/output/json"
+	_ "QuicksilverDumper/output/parquet"
+	_ "QuicksilverDumper/output/postgres"
+	_ "QuicksilverDumper/output/sqlite"
 	"QuicksilverDumper/usecase"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +23,13 @@ var rootCmd = &cobra.Command{
 	Short: "App to query data from a quicksilver-node",
 	Long: `A Go application that can query the following data from a quicksilver-node:
 - Validator to delegator mapping
+- Delegator to validator mapping
+- All accounts with their type, number, sequence and public key type
 - Vesting accounts details categorized by type
+- Vesting schedules evaluated at a point in time
+- Vesting unlock calendar per denom
+- Unbonding delegations and redelegations with their entries
+- Holders of a denom ranked with their share of supply, Gini coefficient and top holders concentration
 - IBC channels statuses between two specified chains
 - Client state for the channels
 - All "pending" receipts in the x/interchainstaking module`,
@@ -25,117 +39,460 @@ var rootCmd = &cobra.Command{
 const (
 	GetPendingStakingReceiptsCmdName     = "pending-staking-receipts"
 	GetChannelsStatusesCmdName           = "channels-statuses"
+	GetChannelsClientStatesCmdName       = "channels-client-states"
+	GetAllAccountsCmdName                = "accounts"
 	GetAllVestingAccountsCmdName         = "vesting-accounts"
+	GetAllVestingSchedulesCmdName        = "vesting-schedules"
+	GetVestingCalendarCmdName            = "vesting-calendar"
+	GetHoldersCmdName                    = "holders"
+	GetUnbondingDelegationsCmdName       = "unbonding-delegations"
+	GetRedelegationsCmdName              = "redelegations"
 	GetAllValidatorsAndDelegatorsCmdName = "validators-delegators"
+	GetAllDelegatorsAndValidatorsCmdName = "delegators-validators"
 )
 
-var node string
+var nodes []string
 var format string
 var outputFile string
+var appendOutput bool
+var normalize bool
+var human bool
+var height int64
+var concurrency int
+var retryPolicy = grpcclient.DefaultRetryPolicy()
+var tlsConfig grpcclient.TLSConfig
+var headers []string
+var counterpartyChainId string
+var portPrefix string
+var at string
+var interval string
+var withBalances bool
+var denom string
+var top int
+var summary bool
 
 var getPendingStakingReceiptsCmd = &cobra.Command{
 	Use:   "pending-staking-receipts",
 	Short: "Query all 'pending' receipts in the x/interchainstaking module",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Infoln("GetPendingStakingReceipts called")
-		err := executeCommand(cmd, args)
-		if err != nil {
-			cmd.ErrOrStderr().Write([]byte(err.Error()))
+		if err := executeCommand(cmd, args); err != nil {
+			return err
 		}
 		logger.Infoln("GetPendingStakingReceipts finished")
+		return nil
 	},
 }
 
 var getChannelsStatusesCmd = &cobra.Command{
 	Use:   "channels-statuses",
 	Short: "Query all IBC channels between two specified chains for their STATUS",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Query all IBC channels for their STATUS.
+Use --counterparty-chain-id to keep only channels whose connection resolves to the given chain
+and --port-prefix to keep only channels whose source port starts with the given prefix.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Infoln("GetChannelsStatuses called")
-		err := executeCommand(cmd, args)
-		if err != nil {
-			cmd.ErrOrStderr().Write([]byte(err.Error()))
+		if err := executeCommand(cmd, args); err != nil {
+			return err
 		}
 		logger.Infoln("GetChannelsStatuses finished")
+		return nil
+	},
+}
+
+var getChannelsClientStatesCmd = &cobra.Command{
+	Use:   "channels-client-states",
+	Short: "Query all IBC channels with their connection and light client state",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetChannelsClientStates called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetChannelsClientStates finished")
+		return nil
+	},
+}
+
+var getAllAccountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Query all accounts: base, module, vesting and interchain accounts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetAllAccounts called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetAllAccounts finished")
+		return nil
 	},
 }
 
 var getAllVestingAccountsCmd = &cobra.Command{
 	Use:   "vesting-accounts",
 	Short: "Query vesting accounts details categorized by type",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Infoln("GetAllVestingAccounts called")
-		err := executeCommand(cmd, args)
-		if err != nil {
-			cmd.ErrOrStderr().Write([]byte(err.Error()))
+		if err := executeCommand(cmd, args); err != nil {
+			return err
 		}
 		logger.Infoln("GetAllVestingAccounts finished")
+		return nil
+	},
+}
+
+var getAllVestingSchedulesCmd = &cobra.Command{
+	Use:   "vesting-schedules",
+	Short: "Query vesting accounts with their vested and vesting coins and next unlock at a point in time",
+	Long: `Query vesting accounts evaluated at --at (default now) with the SDK vesting logic:
+vested and vesting coins, the next unlock time and amount, and the cumulative schedule of periodic accounts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetAllVestingSchedules called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetAllVestingSchedules finished")
+		return nil
+	},
+}
+
+var getVestingCalendarCmd = &cobra.Command{
+	Use:   "vesting-calendar",
+	Short: "Query the unlocks of all vesting accounts bucketed by day, week or month and denom",
+	Long: `Query the unlocks of all vesting accounts after --at (default now), bucketed by --interval and denom
+up to the last vesting end time. Every row holds the period start, the denom, the amount unlocking in the period
+and the cumulative vested amount at the end of the period.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetVestingCalendar called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetVestingCalendar finished")
+		return nil
+	},
+}
+
+var getHoldersCmd = &cobra.Command{
+	Use:   "holders",
+	Short: "Query the holders of a denom ranked by liquid, staked and unbonding amount",
+	Long: `Query every address holding --denom (default: the bond denom), ranked by its total amount with its share of supply.
+For the bond denom the staked and unbonding amounts are added and the staking pools are left out.
+Every row also holds the Gini coefficient and the share of the --top largest holders.
+Use --summary to output only a single row with the supply, holders count, Gini coefficient and top holders share.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetHolders called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetHolders finished")
+		return nil
+	},
+}
+
+var getUnbondingDelegationsCmd = &cobra.Command{
+	Use:   "unbonding-delegations",
+	Short: "Query the unbonding delegations of all validators, one row per entry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetUnbondingDelegations called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetUnbondingDelegations finished")
+		return nil
+	},
+}
+
+var getRedelegationsCmd = &cobra.Command{
+	Use:   "redelegations",
+	Short: "Query the redelegations from all validators, one row per entry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetRedelegations called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetRedelegations finished")
+		return nil
 	},
 }
 
 var getAllValidatorsAndDelegatorsCmd = &cobra.Command{
 	Use:   "validators-delegators",
-	Short: "Query validator to delegator mapping and delegator to validator mapping",
-	Run: func(cmd *cobra.Command, args []string) {
+	Short: "Query validator to delegator mapping",
+	RunE: func(cmd *cobra.Command, args []string) error {
 		logger.Infoln("GetAllValidatorsAndDelegators called")
-		err := executeCommand(cmd, args)
-		if err != nil {
-			cmd.ErrOrStderr().Write([]byte(err.Error()))
+		if err := executeCommand(cmd, args); err != nil {
+			return err
 		}
 		logger.Infoln("GetAllValidatorsAndDelegators finished")
+		return nil
+	},
+}
 
+var getAllDelegatorsAndValidatorsCmd = &cobra.Command{
+	Use:   "delegators-validators",
+	Short: "Query delegator to validator mapping with per-validator and total delegations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Infoln("GetAllDelegatorsAndValidators called")
+		if err := executeCommand(cmd, args); err != nil {
+			return err
+		}
+		logger.Infoln("GetAllDelegatorsAndValidators finished")
+		return nil
 	},
 }
 
 func executeCommand(cmd *cobra.Command, args []string) error {
+	if _, err := output.Lookup(format); err != nil {
+		return err
+	}
+	if appendOutput && !output.CanAppend(format) {
+		return fmt.Errorf("format %s does not support --append", format)
+	}
 
-	client, err := grpcclient.NewGRPCClient(node)
+	dialConfig, err := getDialConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := grpcclient.NewGRPCClient(nodes, dialConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create grpc client: %w", err)
 	}
+	client.SetHeight(height)
+	client.SetConcurrency(concurrency)
+	client.SetRetryPolicy(retryPolicy)
+
+	if len(nodes) > 1 {
+		health, err := client.CheckEndpoints(cmd.Context(), retryPolicy.CallTimeout)
+		for _, h := range health {
+			logger.Infof("endpoint %s", h)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check endpoints: %w", err)
+		}
+		if height == 0 {
+			// pin the height the endpoints were checked against rather than a newer one
+			height = health[0].LatestHeight
+		}
+	}
+
+	if height == 0 {
+		height, err = client.GetLatestHeight(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get latest height: %w", err)
+		}
+	}
+	client.SetHeight(height)
+	if len(nodes) > 1 {
+		for _, url := range client.DropEndpointsBelow(height) {
+			logger.Warnf("endpoint %s dropped from failover: behind height %d", url, height)
+		}
+	}
+	logger.Infof("querying state at height %d", height)
 
 	uc := usecase.NewUseCase(client, logger)
+	uc.Concurrency = concurrency
 
-	var res csvoutput.CsvConvertable
+	var records output.Records
 	switch cmd.Use {
 	case GetPendingStakingReceiptsCmdName:
 		result, err := uc.GetPendingStakingReceipts(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("failed to get pending staking receipts: %w", err)
 		}
-		res = csvoutput.GetPendingStakingReceiptsResponse(result)
+		records = output.PendingStakingReceipts(result)
 	case GetChannelsStatusesCmdName:
-		result, err := uc.GetChannelsStatuses(cmd.Context())
+		result, err := uc.GetChannelsStatuses(cmd.Context(), channelsFilter())
 		if err != nil {
 			return fmt.Errorf("failed to get channels statuses: %w", err)
 		}
-		res = csvoutput.GetChannelsStatusesResponse(result)
+		records = output.ChannelsStatuses(result)
+
+	case GetChannelsClientStatesCmdName:
+		result, err := uc.GetChannelsClientStates(cmd.Context(), channelsFilter())
+		if err != nil {
+			return fmt.Errorf("failed to get channels client states: %w", err)
+		}
+		records = output.ChannelsClientStates(result)
+
+	case GetAllAccountsCmdName:
+		stream := func(fn func([]*usecase.Account) error) error {
+			if err := uc.StreamAllAccounts(cmd.Context(), fn); err != nil {
+				return fmt.Errorf("failed to get all accounts: %w", err)
+			}
+			return nil
+		}
+		records = output.StreamAccounts(stream)
 
 	case GetAllVestingAccountsCmdName:
-		result, err := uc.GetAllVestingAccounts(cmd.Context())
+		if withBalances {
+			stream := func(fn func([]usecase.VestingAccountWithBalances) error) error {
+				if err := uc.StreamAllVestingAccountsWithBalances(cmd.Context(), fn); err != nil {
+					return fmt.Errorf("failed to get all vesting accounts with balances: %w", err)
+				}
+				return nil
+			}
+			records = output.StreamVestingAccountsWithBalances(stream)
+			break
+		}
+		stream := func(fn func([]*usecase.AnyVestingAccount) error) error {
+			if err := uc.StreamAllVestingAccounts(cmd.Context(), fn); err != nil {
+				return fmt.Errorf("failed to get all vesting accounts: %w", err)
+			}
+			return nil
+		}
+		records = output.StreamVestingAccounts(stream)
+
+	case GetAllVestingSchedulesCmdName:
+		evaluatedAt, err := parseTime(at)
+		if err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+		stream := func(fn func([]usecase.VestingSchedule) error) error {
+			if err := uc.StreamAllVestingSchedules(cmd.Context(), evaluatedAt, fn); err != nil {
+				return fmt.Errorf("failed to get all vesting schedules: %w", err)
+			}
+			return nil
+		}
+		records = output.StreamVestingSchedules(stream)
+
+	case GetVestingCalendarCmdName:
+		from, err := parseTime(at)
 		if err != nil {
-			return fmt.Errorf("failed to get all vesting accounts: %w", err)
+			return fmt.Errorf("invalid --at: %w", err)
 		}
-		res = csvoutput.GetAllVestingAccountsResponse(result)
+		calendarInterval, err := usecase.ParseCalendarInterval(interval)
+		if err != nil {
+			return fmt.Errorf("invalid --interval: %w", err)
+		}
+		result, err := uc.GetVestingCalendar(cmd.Context(), from, calendarInterval)
+		if err != nil {
+			return fmt.Errorf("failed to get vesting calendar: %w", err)
+		}
+		records = output.VestingCalendar(result)
+
+	case GetHoldersCmdName:
+		result, err := uc.GetHolders(cmd.Context(), denom)
+		if err != nil {
+			return fmt.Errorf("failed to get holders: %w", err)
+		}
+		logger.Infof("%s: %d holders, gini %s, top %d hold %s of the supply", result.Denom, len(result.Holders), result.Gini, top, result.TopShare(top))
+		if summary {
+			records = output.HoldersSummary(result, top)
+		} else {
+			records = output.Holders(result, top)
+		}
+
+	case GetUnbondingDelegationsCmdName:
+		stream := func(fn func([]usecase.UnbondingEntry) error) error {
+			if err := uc.StreamAllUnbondingEntries(cmd.Context(), fn); err != nil {
+				return fmt.Errorf("failed to get all unbonding delegations: %w", err)
+			}
+			return nil
+		}
+		records = output.StreamUnbondingEntries(stream)
+
+	case GetRedelegationsCmdName:
+		stream := func(fn func([]usecase.RedelegationEntry) error) error {
+			if err := uc.StreamAllRedelegationEntries(cmd.Context(), fn); err != nil {
+				return fmt.Errorf("failed to get all redelegations: %w", err)
+			}
+			return nil
+		}
+		records = output.StreamRedelegationEntries(stream)
 
 	case GetAllValidatorsAndDelegatorsCmdName:
-		result, err := uc.GetAllValidatorsAndDelegators(cmd.Context())
+		stream := func(fn func([]usecase.ValidatorWithDelegators) error) error {
+			if err := uc.StreamAllValidatorsAndDelegators(cmd.Context(), fn); err != nil {
+				return fmt.Errorf("failed to get all validators and delegators: %w", err)
+			}
+			return nil
+		}
+		records = output.StreamValidatorsAndDelegators(stream)
+
+	case GetAllDelegatorsAndValidatorsCmdName:
+		result, err := uc.GetAllDelegatorsAndValidators(cmd.Context())
 		if err != nil {
-			return fmt.Errorf("failed to get all validators and delegators: %w", err)
+			return fmt.Errorf("failed to get all delegators and validators: %w", err)
 		}
-		res = csvoutput.GetAllValidatorsAndDelegatorsResponse(result)
+		records = output.DelegatorsAndValidators(result)
 
 	default:
 		return fmt.Errorf("unknown command: %s", cmd.Short)
 
 	}
 
-	logger.Infof("writing %s result into %s in %s format", cmd.Use, outputFile, format)
-	outputer, err := output.GetCSVOutputer(res)
+	if human {
+		units, err := uc.GetDisplayUnits(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get display units: %w", err)
+		}
+		records = output.Humanize(records, units)
+	}
+	if normalize {
+		records = output.Normalize(records)
+	}
+	outputer, err := output.GetOutputer(format, output.WithHeight(records, client.Height()))
 	if err != nil {
 		return fmt.Errorf("failed to get outputer: %w", err)
 	}
+
+	logger.Infof("writing %s result into %s in %s format", cmd.Use, outputTarget(), format)
+	if appendOutput {
+		// CanAppend was checked before querying
+		if err := outputer.(output.Appender).AppendToFile(outputFile); err != nil {
+			return fmt.Errorf("failed to output: %w", err)
+		}
+		return nil
+	}
 	if err := outputer.WriteToFile(outputFile); err != nil {
 		return fmt.Errorf("failed to output: %w", err)
 	}
 	return nil
 }
+
+// outputTarget returns the output for logging, without the password of database connection strings
+func outputTarget() string {
+	if output.IsStdout(outputFile) {
+		return "stdout"
+	}
+	if u, err := url.Parse(outputFile); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return outputFile
+}
+
+// parseTime parses an RFC3339 timestamp, a date or unix seconds, an empty value is the current time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp, a YYYY-MM-DD date nor unix seconds", value)
+}
+
+func channelsFilter() usecase.ChannelsFilter {
+	return usecase.ChannelsFilter{
+		CounterpartyChainId: counterpartyChainId,
+		PortPrefix:          portPrefix,
+	}
+}
+
+// getDialConfig builds the connection config from the TLS and "key=value" header flags
+func getDialConfig() (grpcclient.DialConfig, error) {
+	cfg := grpcclient.DialConfig{TLS: tlsConfig, Headers: map[string]string{}}
+	for _, header := range headers {
+		key, value, ok := strings.Cut(header, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return cfg, fmt.Errorf("invalid header %q, expected key=value", header)
+		}
+		cfg.Headers[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return cfg, nil
+}
@@ -1,29 +1,80 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strings"
 
+	"QuicksilverDumper/output"
+	"QuicksilverDumper/usecase"
+
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
 var logger *zap.SugaredLogger
 
 func main() {
-	l, err := zap.NewDevelopment()
+	cfg := zap.NewDevelopmentConfig()
+	// logs always go to stderr, stdout is kept for the output itself
+	cfg.OutputPaths = []string{"stderr"}
+	cfg.ErrorOutputPaths = []string{"stderr"}
+	l, err := cfg.Build()
 	if err != nil {
 		log.Fatalf("could not initialize zap logger: %v", err)
 	}
 	logger = l.Sugar()
 
-	rootCmd.PersistentFlags().StringVar(&node, "node", "quicksilver.grpc.kjnodes.com:11190", "Node URL to connect to")
-	rootCmd.PersistentFlags().StringVar(&format, "format", "csv", "Output format (csv)")
-	rootCmd.PersistentFlags().StringVar(&outputFile, "output", "", "Where to store response")
+	rootCmd.PersistentFlags().StringSliceVar(&nodes, "node", []string{"quicksilver.grpc.kjnodes.com:11190"}, "Node URL to connect to, several nodes (comma separated or repeated) are health-checked and used for failover")
+	rootCmd.PersistentFlags().StringVar(&format, "format", "csv", fmt.Sprintf("Output format (%s)", strings.Join(output.Formats(), ", ")))
+	rootCmd.PersistentFlags().StringVar(&outputFile, "output", output.Stdout, "Where to store response, - streams it to stdout")
+	rootCmd.PersistentFlags().BoolVar(&appendOutput, "append", false, "Append rows to the output file instead of replacing it, headers are only written into a new file (csv, jsonl)")
+	rootCmd.PersistentFlags().BoolVar(&normalize, "normalize", false, "One row per denom with separate denom and amount columns instead of coins columns")
+	rootCmd.PersistentFlags().BoolVar(&human, "human", false, "Convert coins into display units from the bank denom metadata, e.g. uqck into QCK")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 4, "Maximum number of parallel per-validator, per-zone and per-account queries")
+	rootCmd.PersistentFlags().BoolVar(&tlsConfig.Enabled, "tls", false, "Connect to the node over TLS verified by the system roots")
+	rootCmd.PersistentFlags().StringVar(&tlsConfig.CAFile, "tls-ca", "", "PEM CA bundle to verify the node with instead of the system roots (implies --tls)")
+	rootCmd.PersistentFlags().StringVar(&tlsConfig.CertFile, "tls-cert", "", "PEM client certificate for mutual TLS (implies --tls)")
+	rootCmd.PersistentFlags().StringVar(&tlsConfig.KeyFile, "tls-key", "", "PEM client key for mutual TLS (implies --tls)")
+	rootCmd.PersistentFlags().BoolVar(&tlsConfig.SkipVerify, "tls-skip-verify", false, "Do not verify the node certificate (implies --tls)")
+	rootCmd.PersistentFlags().StringArrayVar(&headers, "header", nil, "Metadata header sent with every call as key=value, e.g. x-api-key=secret (repeatable)")
+	rootCmd.PersistentFlags().IntVar(&retryPolicy.MaxAttempts, "retry-attempts", retryPolicy.MaxAttempts, "Maximum attempts per gRPC call on Unavailable, ResourceExhausted, Aborted or DeadlineExceeded")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.InitialBackoff, "retry-backoff", retryPolicy.InitialBackoff, "Initial backoff between retries, doubled on every retry with random jitter")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.MaxBackoff, "retry-max-backoff", retryPolicy.MaxBackoff, "Maximum backoff between retries")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.CallTimeout, "call-timeout", retryPolicy.CallTimeout, "Timeout of a single gRPC call attempt (0 disables it)")
+	rootCmd.PersistentFlags().Int64Var(&height, "height", 0, "Block height to query all data at (default: latest height at the start of the run)")
+
+	for _, cmd := range []*cobra.Command{getChannelsStatusesCmd, getChannelsClientStatesCmd} {
+		cmd.Flags().StringVar(&counterpartyChainId, "counterparty-chain-id", "", "Only channels whose connection resolves to this counterparty chain ID")
+		cmd.Flags().StringVar(&portPrefix, "port-prefix", "", "Only channels whose source port starts with this prefix (e.g. icacontroller-cosmoshub-4)")
+	}
+
+	getAllVestingAccountsCmd.Flags().BoolVar(&withBalances, "balances", false, "Join every account with its bank balances (total and spendable), delegations and unbonding delegations")
+	getAllVestingSchedulesCmd.Flags().StringVar(&at, "at", "", "Time to evaluate the schedules at, RFC3339, YYYY-MM-DD or unix seconds (default: now)")
+	getVestingCalendarCmd.Flags().StringVar(&at, "at", "", "Time to start the calendar at, RFC3339, YYYY-MM-DD or unix seconds (default: now)")
+	getVestingCalendarCmd.Flags().StringVar(&interval, "interval", string(usecase.Month), "Calendar bucket: day, week or month")
+
+	getHoldersCmd.Flags().StringVar(&denom, "denom", "", "Denom to rank the holders of, e.g. uqck or uqatom (default: the bond denom)")
+	getHoldersCmd.Flags().IntVar(&top, "top", 10, "Number of largest holders to compute the concentration of")
+	getHoldersCmd.Flags().BoolVar(&summary, "summary", false, "Output only a single row with the supply, holders count, Gini coefficient and top holders share instead of the ranked holders")
 
 	rootCmd.AddCommand(getPendingStakingReceiptsCmd)
 	rootCmd.AddCommand(getChannelsStatusesCmd)
+	rootCmd.AddCommand(getChannelsClientStatesCmd)
+	rootCmd.AddCommand(getAllAccountsCmd)
 	rootCmd.AddCommand(getAllVestingAccountsCmd)
+	rootCmd.AddCommand(getAllVestingSchedulesCmd)
+	rootCmd.AddCommand(getVestingCalendarCmd)
+	rootCmd.AddCommand(getHoldersCmd)
+	rootCmd.AddCommand(getUnbondingDelegationsCmd)
+	rootCmd.AddCommand(getRedelegationsCmd)
 	rootCmd.AddCommand(getAllValidatorsAndDelegatorsCmd)
+	rootCmd.AddCommand(getAllDelegatorsAndValidatorsCmd)
 
-	rootCmd.Execute()
-
+	// errors are printed by cobra on their own line, the exit status tells pipelines that the output is incomplete
+	rootCmd.SilenceUsage = true
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,510 @@
+package output
+
+import (
+	"strings"
+	"time"
+
+	"QuicksilverDumper/usecase"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	icstypes "github.com/ingenuity-build/quicksilver/x/interchainstaking/types"
+)
+
+var pendingStakingReceiptsSchema = Schema{Name: "pending_staking_receipts", Columns: []Column{
+	{Name: "ChainId", Type: String},
+	{Name: "Sender", Type: String},
+	{Name: "Txhash", Type: String},
+	{Name: "Coins", Type: Coins},
+	{Name: "FirstSeen", Type: Time},
+	{Name: "Completed", Type: Time},
+}, Key: []string{"Txhash"}}
+
+func PendingStakingReceipts(receipts []icstypes.Receipt) Records {
+	rows := make([]Row, 0, len(receipts))
+	for _, receipt := range receipts {
+		rows = append(rows, Row{
+			receipt.ChainId,
+			receipt.Sender,
+			receipt.Txhash,
+			sdk.Coins(receipt.Amount),
+			receipt.FirstSeen,
+			receipt.Completed,
+		})
+	}
+	return StaticRecords(pendingStakingReceiptsSchema, rows)
+}
+
+var channelStatusKey = []string{"SourcePortId", "SourceChannelId"}
+
+var channelStatusColumns = []Column{
+	{Name: "SourceChannelId", Type: String},
+	{Name: "SourcePortId", Type: String},
+	{Name: "CounterpartyChannelId", Type: String},
+	{Name: "CounterpartyPortId", Type: String},
+	{Name: "State", Type: String},
+}
+
+func channelStatusRow(cs *usecase.ChannelStatus) Row {
+	return Row{
+		cs.SourceChannelId,
+		cs.SourcePortId,
+		cs.CounterpartyChannelId,
+		cs.CounterpartyPortId,
+		cs.State,
+	}
+}
+
+func ChannelsStatuses(channels []*usecase.ChannelStatus) Records {
+	rows := make([]Row, 0, len(channels))
+	for _, cs := range channels {
+		rows = append(rows, channelStatusRow(cs))
+	}
+	return StaticRecords(Schema{Name: "channels_statuses", Columns: channelStatusColumns, Key: channelStatusKey}, rows)
+}
+
+var channelsClientStatesSchema = Schema{Name: "channels_client_states", Columns: append(append([]Column{}, channelStatusColumns...),
+	Column{Name: "ConnectionId", Type: String},
+	Column{Name: "ConnectionState", Type: String},
+	Column{Name: "ClientId", Type: String},
+	Column{Name: "ClientType", Type: String},
+	Column{Name: "CounterpartyChainId", Type: String},
+	Column{Name: "LatestHeight", Type: String},
+	Column{Name: "TrustingPeriodSeconds", Type: Int},
+	Column{Name: "FrozenHeight", Type: String},
+	Column{Name: "ClientStatus", Type: String},
+), Key: channelStatusKey}
+
+func ChannelsClientStates(states []*usecase.ChannelClientState) Records {
+	rows := make([]Row, 0, len(states))
+	for _, cs := range states {
+		rows = append(rows, append(channelStatusRow(&cs.ChannelStatus),
+			cs.ConnectionId,
+			cs.ConnectionState,
+			cs.ClientId,
+			cs.ClientType,
+			cs.CounterpartyChainId,
+			cs.LatestHeight,
+			int64(cs.TrustingPeriod.Seconds()),
+			cs.FrozenHeight,
+			cs.Status,
+		))
+	}
+	return StaticRecords(channelsClientStatesSchema, rows)
+}
+
+var accountsSchema = Schema{Name: "accounts", Columns: []Column{
+	{Name: "Account Type", Type: String},
+	{Name: "Account Address", Type: String},
+	{Name: "Account Number", Type: Int},
+	{Name: "Sequence", Type: Int},
+	{Name: "Pub Key Type", Type: String},
+	{Name: "Module Name", Type: String},
+	{Name: "Permissions", Type: String},
+	{Name: "Owner", Type: String},
+}, Key: []string{"Account Address"}}
+
+func accountsRows(accounts []*usecase.Account) []Row {
+	rows := make([]Row, 0, len(accounts))
+	for _, account := range accounts {
+		rows = append(rows, Row{
+			account.Type,
+			account.Address,
+			int64(account.AccountNumber),
+			int64(account.Sequence),
+			account.PubKeyType,
+			account.ModuleName,
+			strings.Join(account.Permissions, ","),
+			account.Owner,
+		})
+	}
+	return rows
+}
+
+// StreamAccounts converts accounts pages, e.g. usecase.StreamAllAccounts bound to a context
+func StreamAccounts(stream func(fn func([]*usecase.Account) error) error) Records {
+	return Records{
+		Schema: accountsSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(accounts []*usecase.Account) error {
+				return fn(accountsRows(accounts))
+			})
+		},
+	}
+}
+
+var vestingAccountsSchema = Schema{Name: "vesting_accounts", Columns: []Column{
+	{Name: "Account Type", Type: String},
+	{Name: "Account Address", Type: String},
+	{Name: "Original Vesting", Type: Coins},
+	{Name: "Delegated Free", Type: Coins},
+	{Name: "Delegated Vesting", Type: Coins},
+	{Name: "End Time", Type: Time},
+	{Name: "Start Time", Type: Time},
+	{Name: "Periods", Type: List, Fields: []Column{
+		{Name: "Length", Type: Int},
+		{Name: "Amount", Type: Coins},
+	}},
+}, Key: []string{"Account Address"}}
+
+// unixTime converts a unix timestamp into a time value
+func unixTime(unix int64) *time.Time {
+	t := time.Unix(unix, 0).UTC()
+	return &t
+}
+
+func vestingAccountRow(accountType usecase.VestingAccountType, base *vestingtypes.BaseVestingAccount) Row {
+	return Row{
+		string(accountType),
+		base.Address,
+		base.OriginalVesting,
+		base.DelegatedFree,
+		base.DelegatedVesting,
+		nil,
+		nil,
+		nil,
+	}
+}
+
+func vestingAccountsRows(accounts []*usecase.AnyVestingAccount) []Row {
+	rows := make([]Row, 0, len(accounts))
+	for _, account := range accounts {
+		var row Row
+		switch account.AccountType {
+		case usecase.Delayed:
+			row = vestingAccountRow(account.AccountType, account.Delayed.BaseVestingAccount)
+			row[5] = unixTime(account.Delayed.EndTime)
+		case usecase.Continuous:
+			row = vestingAccountRow(account.AccountType, account.Continuous.BaseVestingAccount)
+			row[5] = unixTime(account.Continuous.EndTime)
+			row[6] = unixTime(account.Continuous.StartTime)
+		case usecase.Periodic:
+			row = vestingAccountRow(account.AccountType, account.Periodic.BaseVestingAccount)
+			row[5] = unixTime(account.Periodic.EndTime)
+			row[6] = unixTime(account.Periodic.StartTime)
+			periods := make([]Row, 0, len(account.Periodic.VestingPeriods))
+			for _, period := range account.Periodic.VestingPeriods {
+				periods = append(periods, Row{period.Length, period.Amount})
+			}
+			row[7] = periods
+		case usecase.PermanentLocked:
+			row = vestingAccountRow(account.AccountType, account.PermanentLocked.BaseVestingAccount)
+		default:
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// StreamVestingAccounts converts vesting accounts pages, e.g. usecase.StreamAllVestingAccounts bound to a context
+func StreamVestingAccounts(stream func(fn func([]*usecase.AnyVestingAccount) error) error) Records {
+	return Records{
+		Schema: vestingAccountsSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(accounts []*usecase.AnyVestingAccount) error {
+				return fn(vestingAccountsRows(accounts))
+			})
+		},
+	}
+}
+
+var vestingAccountsWithBalancesSchema = Schema{Name: "vesting_accounts_balances", Columns: append(append([]Column{}, vestingAccountsSchema.Columns...),
+	Column{Name: "Balances", Type: Coins},
+	Column{Name: "Spendable", Type: Coins},
+	Column{Name: "Delegated", Type: Coins},
+	Column{Name: "Unbonding", Type: Coins},
+), Key: vestingAccountsSchema.Key}
+
+// StreamVestingAccountsWithBalances converts vesting accounts with balances pages,
+// e.g. usecase.StreamAllVestingAccountsWithBalances bound to a context
+func StreamVestingAccountsWithBalances(stream func(fn func([]usecase.VestingAccountWithBalances) error) error) Records {
+	return Records{
+		Schema: vestingAccountsWithBalancesSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(joined []usecase.VestingAccountWithBalances) error {
+				rows := make([]Row, 0, len(joined))
+				for _, account := range joined {
+					accountRows := vestingAccountsRows([]*usecase.AnyVestingAccount{account.Account})
+					if len(accountRows) == 0 {
+						continue
+					}
+					rows = append(rows, append(accountRows[0], account.Balances, account.Spendable, account.Delegated, account.Unbonding))
+				}
+				return fn(rows)
+			})
+		},
+	}
+}
+
+var vestingSchedulesSchema = Schema{Name: "vesting_schedules", Columns: []Column{
+	{Name: "Account Type", Type: String},
+	{Name: "Account Address", Type: String},
+	{Name: "At", Type: Time},
+	{Name: "Original Vesting", Type: Coins},
+	{Name: "Vested", Type: Coins},
+	{Name: "Vesting", Type: Coins},
+	{Name: "Next Unlock Time", Type: Time},
+	{Name: "Next Unlock Amount", Type: Coins},
+	{Name: "Periods", Type: List, Fields: []Column{
+		{Name: "End Time", Type: Time},
+		{Name: "Amount", Type: Coins},
+		{Name: "Cumulative", Type: Coins},
+	}},
+}, Key: []string{"Account Address"}}
+
+func vestingSchedulesRows(schedules []usecase.VestingSchedule) []Row {
+	rows := make([]Row, 0, len(schedules))
+	for _, schedule := range schedules {
+		base := schedule.Account.Base()
+		at := schedule.At.UTC()
+		row := Row{
+			string(schedule.Account.AccountType),
+			base.Address,
+			&at,
+			base.OriginalVesting,
+			schedule.Vested,
+			schedule.Vesting,
+			nil,
+			nil,
+			nil,
+		}
+		if schedule.NextUnlockTime != nil {
+			row[6] = schedule.NextUnlockTime
+			row[7] = schedule.NextUnlockAmount
+		}
+		if schedule.Account.AccountType == usecase.Periodic {
+			periods := make([]Row, 0, len(schedule.Periods))
+			for _, period := range schedule.Periods {
+				endTime := period.EndTime
+				periods = append(periods, Row{&endTime, period.Amount, period.Cumulative})
+			}
+			row[8] = periods
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// StreamVestingSchedules converts vesting schedules pages, e.g. usecase.StreamAllVestingSchedules bound to a context
+func StreamVestingSchedules(stream func(fn func([]usecase.VestingSchedule) error) error) Records {
+	return Records{
+		Schema: vestingSchedulesSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(schedules []usecase.VestingSchedule) error {
+				return fn(vestingSchedulesRows(schedules))
+			})
+		},
+	}
+}
+
+var vestingCalendarSchema = Schema{Name: "vesting_calendar", Columns: []Column{
+	{Name: "Period Start", Type: Time},
+	{Name: "Denom", Type: String},
+	{Name: "Amount", Type: BigInt},
+	{Name: "Cumulative", Type: BigInt},
+}, Key: []string{"Period Start", "Denom"}}
+
+func VestingCalendar(calendar []usecase.VestingCalendarEntry) Records {
+	rows := make([]Row, 0, len(calendar))
+	for _, entry := range calendar {
+		periodStart := entry.PeriodStart
+		rows = append(rows, Row{&periodStart, entry.Denom, entry.Amount, entry.Cumulative})
+	}
+	return StaticRecords(vestingCalendarSchema, rows)
+}
+
+var holdersSchema = Schema{Name: "holders", Columns: []Column{
+	{Name: "Rank", Type: Int},
+	{Name: "Address", Type: String},
+	{Name: "Denom", Type: String},
+	{Name: "Liquid", Type: BigInt},
+	{Name: "Staked", Type: BigInt},
+	{Name: "Unbonding", Type: BigInt},
+	{Name: "Total", Type: BigInt},
+	{Name: "Share", Type: Decimal},
+	{Name: "Cumulative Share", Type: Decimal},
+	{Name: "Gini", Type: Decimal},
+	{Name: "Top", Type: Int},
+	{Name: "Top Share", Type: Decimal},
+}, Key: []string{"Denom", "Address"}}
+
+// Holders is the ranked list of holders, every row also holds the concentration of the whole report as in HoldersSummary
+func Holders(report *usecase.HoldersReport, top int) Records {
+	topShare := report.TopShare(top)
+	rows := make([]Row, 0, len(report.Holders))
+	for _, h := range report.Holders {
+		rows = append(rows, Row{
+			int64(h.Rank),
+			h.Address,
+			report.Denom,
+			h.Liquid,
+			h.Staked,
+			h.Unbonding,
+			h.Total,
+			h.Share,
+			h.CumulativeShare,
+			report.Gini,
+			int64(top),
+			topShare,
+		})
+	}
+	return StaticRecords(holdersSchema, rows)
+}
+
+var holdersSummarySchema = Schema{Name: "holders_summary", Columns: []Column{
+	{Name: "Denom", Type: String},
+	{Name: "Supply", Type: BigInt},
+	{Name: "Holders", Type: Int},
+	{Name: "Gini", Type: Decimal},
+	{Name: "Top", Type: Int},
+	{Name: "Top Share", Type: Decimal},
+}, Key: []string{"Denom"}}
+
+// HoldersSummary is a single row with the concentration of the report, Top Share being the share of the top largest holders
+func HoldersSummary(report *usecase.HoldersReport, top int) Records {
+	return StaticRecords(holdersSummarySchema, []Row{{
+		report.Denom,
+		report.Supply,
+		int64(len(report.Holders)),
+		report.Gini,
+		int64(top),
+		report.TopShare(top),
+	}})
+}
+
+var validatorsAndDelegatorsSchema = Schema{Name: "validators_delegators", Columns: []Column{
+	{Name: "ValidatorAddress", Type: String},
+	{Name: "DelegatorAddress", Type: String},
+	{Name: "Delegations", Type: Coins},
+	{Name: "TotalShares", Type: Decimal},
+}, Key: []string{"ValidatorAddress", "DelegatorAddress"}}
+
+func validatorsAndDelegatorsRows(validators []usecase.ValidatorWithDelegators) []Row {
+	rows := make([]Row, 0, len(validators))
+	for _, vwd := range validators {
+		rows = append(rows, Row{vwd.ValidatorAddress, vwd.DelegatorAddress, vwd.Delegations, vwd.TotalShares})
+	}
+	return rows
+}
+
+// StreamValidatorsAndDelegators converts validator delegators pages, e.g. usecase.StreamAllValidatorsAndDelegators bound to a context
+func StreamValidatorsAndDelegators(stream func(fn func([]usecase.ValidatorWithDelegators) error) error) Records {
+	return Records{
+		Schema: validatorsAndDelegatorsSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(validators []usecase.ValidatorWithDelegators) error {
+				return fn(validatorsAndDelegatorsRows(validators))
+			})
+		},
+	}
+}
+
+// coins wraps a single coin, a zero coin gives no coins like sdk.NewCoins without its denom validation
+func coins(coin sdk.Coin) sdk.Coins {
+	if coin.Amount.IsNil() || coin.Amount.IsZero() {
+		return sdk.Coins{}
+	}
+	return sdk.Coins{coin}
+}
+
+var unbondingEntriesSchema = Schema{Name: "unbonding_delegations", Columns: []Column{
+	{Name: "DelegatorAddress", Type: String},
+	{Name: "ValidatorAddress", Type: String},
+	{Name: "Entry", Type: Int},
+	{Name: "CreationHeight", Type: Int},
+	{Name: "CompletionTime", Type: Time},
+	{Name: "InitialBalance", Type: Coins},
+	{Name: "Balance", Type: Coins},
+}, Key: []string{"ValidatorAddress", "DelegatorAddress", "CreationHeight", "CompletionTime", "Entry"}}
+
+// StreamUnbondingEntries converts unbonding entries pages, e.g. usecase.StreamAllUnbondingEntries bound to a context
+func StreamUnbondingEntries(stream func(fn func([]usecase.UnbondingEntry) error) error) Records {
+	return Records{
+		Schema: unbondingEntriesSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(entries []usecase.UnbondingEntry) error {
+				rows := make([]Row, 0, len(entries))
+				for _, entry := range entries {
+					completionTime := entry.CompletionTime.UTC()
+					rows = append(rows, Row{
+						entry.DelegatorAddress,
+						entry.ValidatorAddress,
+						int64(entry.Entry),
+						entry.CreationHeight,
+						&completionTime,
+						coins(entry.InitialBalance),
+						coins(entry.Balance),
+					})
+				}
+				return fn(rows)
+			})
+		},
+	}
+}
+
+var redelegationEntriesSchema = Schema{Name: "redelegations", Columns: []Column{
+	{Name: "DelegatorAddress", Type: String},
+	{Name: "SrcValidatorAddress", Type: String},
+	{Name: "DstValidatorAddress", Type: String},
+	{Name: "Entry", Type: Int},
+	{Name: "CreationHeight", Type: Int},
+	{Name: "CompletionTime", Type: Time},
+	{Name: "InitialBalance", Type: Coins},
+	{Name: "Balance", Type: Coins},
+	{Name: "SharesDst", Type: Decimal},
+}, Key: []string{"SrcValidatorAddress", "DstValidatorAddress", "DelegatorAddress", "CreationHeight", "CompletionTime", "Entry"}}
+
+// StreamRedelegationEntries converts redelegation entries pages, e.g. usecase.StreamAllRedelegationEntries bound to a context
+func StreamRedelegationEntries(stream func(fn func([]usecase.RedelegationEntry) error) error) Records {
+	return Records{
+		Schema: redelegationEntriesSchema,
+		Stream: func(fn func(rows []Row) error) error {
+			return stream(func(entries []usecase.RedelegationEntry) error {
+				rows := make([]Row, 0, len(entries))
+				for _, entry := range entries {
+					completionTime := entry.CompletionTime.UTC()
+					rows = append(rows, Row{
+						entry.DelegatorAddress,
+						entry.SrcValidatorAddress,
+						entry.DstValidatorAddress,
+						int64(entry.Entry),
+						entry.CreationHeight,
+						&completionTime,
+						coins(entry.InitialBalance),
+						coins(entry.Balance),
+						entry.SharesDst,
+					})
+				}
+				return fn(rows)
+			})
+		},
+	}
+}
+
+var delegatorsAndValidatorsSchema = Schema{Name: "delegators_validators", Columns: []Column{
+	{Name: "DelegatorAddress", Type: String},
+	{Name: "ValidatorsCount", Type: Int},
+	{Name: "Validators", Type: List, Fields: []Column{
+		{Name: "ValidatorAddress", Type: String},
+		{Name: "Delegations", Type: Coins},
+		{Name: "Shares", Type: Decimal},
+	}},
+	{Name: "TotalDelegations", Type: Coins},
+}, Key: []string{"DelegatorAddress"}}
+
+func DelegatorsAndValidators(delegators []usecase.DelegatorWithValidators) Records {
+	rows := make([]Row, 0, len(delegators))
+	for _, dwv := range delegators {
+		validators := make([]Row, 0, len(dwv.Validators))
+		for _, v := range dwv.Validators {
+			validators = append(validators, Row{v.ValidatorAddress, v.Delegations, v.Shares})
+		}
+		rows = append(rows, Row{
+			dwv.DelegatorAddress,
+			int64(len(dwv.Validators)),
+			validators,
+			dwv.TotalDelegations,
+		})
+	}
+	return StaticRecords(delegatorsAndValidatorsSchema, rows)
+}
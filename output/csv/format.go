@@ -0,0 +1,82 @@
+package csvoutput
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"QuicksilverDumper/output"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func GetHeaders(schema output.Schema) []string {
+	headers := make([]string, 0, len(schema.Columns))
+	for _, column := range schema.Columns {
+		headers = append(headers, column.Name)
+	}
+	return headers
+}
+
+func GetValues(schema output.Schema, rows []output.Row) [][]string {
+	legacy := legacyColumns[schema.Name]
+	values := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		value := make([]string, 0, len(row))
+		for i, column := range schema.Columns {
+			if format, ok := legacy[column.Name]; ok {
+				value = append(value, format(column, row[i]))
+				continue
+			}
+			value = append(value, formatValue(column, row[i]))
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// formatValue renders a single value, nulls are rendered as empty strings
+func formatValue(column output.Column, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case sdk.Int:
+		return v.String()
+	case sdk.Dec:
+		if column.Type == output.DisplayAmount {
+			return output.FormatDisplayAmount(v)
+		}
+		return v.String()
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case sdk.Coins:
+		return v.String()
+	case sdk.DecCoins:
+		coins := make([]string, 0, len(v))
+		for _, coin := range v {
+			coins = append(coins, output.FormatDisplayAmount(coin.Amount)+coin.Denom)
+		}
+		return strings.Join(coins, ",")
+	case []output.Row:
+		// e.g. {length: 7729200, amount: 78150174uqck}; {length: 2678400, amount: 26050058uqck}
+		elements := make([]string, 0, len(v))
+		for _, element := range v {
+			fields := make([]string, 0, len(column.Fields))
+			for i, field := range column.Fields {
+				fields = append(fields, fmt.Sprintf("%s: %s", field.Key(), formatValue(field, element[i])))
+			}
+			elements = append(elements, fmt.Sprintf("{%s}", strings.Join(fields, ", ")))
+		}
+		return strings.Join(elements, "; ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,77 @@
+package csvoutput
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"QuicksilverDumper/output"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type legacyFormat func(column output.Column, value interface{}) string
+
+// legacyColumns render the columns of the tasks written as CSV before typed records the way they were,
+// so consumers of existing files (accs.csv, rcps.csv, val-degs.csv) keep parsing them, keyed by schema and column name
+var legacyColumns = map[string]map[string]legacyFormat{
+	"pending_staking_receipts": {
+		"Coins":     formatReceiptCoins,
+		"FirstSeen": formatReceiptTime,
+		"Completed": formatReceiptTime,
+	},
+	"vesting_accounts": {
+		"End Time":   formatVestingTime,
+		"Start Time": formatVestingTime,
+		"Periods":    formatVestingPeriods,
+	},
+	"validators_delegators": {
+		"Delegations": formatDelegations,
+	},
+}
+
+// formatReceiptCoins renders coins as [denom,{amount},...], e.g. [ustars,{1374415880480}]
+func formatReceiptCoins(_ output.Column, value interface{}) string {
+	coins, _ := value.(sdk.Coins)
+	amounts := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		amounts = append(amounts, fmt.Sprintf("%s,{%s}", coin.Denom, coin.Amount))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(amounts, ","))
+}
+
+// formatReceiptTime renders times as Go does, e.g. 2023-06-13 21:27:06.563483964 +0000 UTC, and nulls as null
+func formatReceiptTime(_ output.Column, value interface{}) string {
+	t, _ := value.(*time.Time)
+	if t == nil {
+		return "null"
+	}
+	return t.String()
+}
+
+// formatVestingTime renders times as unix seconds and nulls as N/A
+func formatVestingTime(_ output.Column, value interface{}) string {
+	t, _ := value.(*time.Time)
+	if t == nil {
+		return "N/A"
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// formatVestingPeriods renders periods as any list and nulls as N/A
+func formatVestingPeriods(column output.Column, value interface{}) string {
+	if value == nil {
+		return "N/A"
+	}
+	return formatValue(column, value)
+}
+
+// formatDelegations renders coins as [{denom,amount},...], e.g. [{uqck,50000000}]
+func formatDelegations(_ output.Column, value interface{}) string {
+	coins, _ := value.(sdk.Coins)
+	amounts := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		amounts = append(amounts, fmt.Sprintf("{%s,%s}", coin.Denom, coin.Amount))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(amounts, ","))
+}
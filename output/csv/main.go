@@ -1,43 +1,60 @@
 package csvoutput
 
 import (
+	"bufio"
 	"encoding/csv"
 	"fmt"
-	"os"
-	"path/filepath"
+
+	"QuicksilverDumper/output"
 )
 
+func init() {
+	output.Register("csv", func(records output.Records) (output.Outputer, error) {
+		return NewCsvOutputer(records), nil
+	})
+}
+
 type CsvOutputer struct {
-	value CsvConvertable
+	records output.Records
 }
 
-func NewCsvOutputer(val interface{}) (CsvOutputer, error) {
-	if value, ok := val.(CsvConvertable); !ok {
-		return CsvOutputer{}, fmt.Errorf("value is not CsvConvertable")
-	} else {
-		return CsvOutputer{value: value}, nil
-	}
+func NewCsvOutputer(records output.Records) CsvOutputer {
+	return CsvOutputer{records: records}
 }
 
+// WriteToFile replaces the file at path atomically
 func (c CsvOutputer) WriteToFile(path string) error {
-	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
-	// Open a CSV file
-	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	err := output.WriteFileAtomic(path, func(w *bufio.Writer) error {
+		return c.write(w, true)
+	})
 	if err != nil {
-		return fmt.Errorf("could not create CSV file: %e", err)
-
+		return fmt.Errorf("could not write to CSV file: %w", err)
 	}
-	defer file.Close()
+	return nil
+}
 
-	// Create a CSV writer
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// AppendToFile appends the rows to the file at path, headers are only written into an empty file
+func (c CsvOutputer) AppendToFile(path string) error {
+	if err := output.AppendFile(path, c.write); err != nil {
+		return fmt.Errorf("could not append to CSV file: %w", err)
+	}
+	return nil
+}
 
-	if err = writer.Write(c.value.GetHeaders()); err != nil {
-		return fmt.Errorf("could not write to CSV file: %e", err)
+func (c CsvOutputer) write(w *bufio.Writer, headers bool) error {
+	writer := csv.NewWriter(w)
+	if headers {
+		if err := writer.Write(GetHeaders(c.records.Schema)); err != nil {
+			return err
+		}
 	}
-	if err = writer.WriteAll(c.value.GetValues()); err != nil {
-		return fmt.Errorf("could not write to CSV file: %e", err)
+	err := c.records.Stream(func(rows []output.Row) error {
+		// flush every batch so rows are not buffered until the stream ends
+		return writer.WriteAll(GetValues(c.records.Schema, rows))
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	writer.Flush()
+	return writer.Error()
 }
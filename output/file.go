@@ -0,0 +1,133 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Stdout is the output path streaming file formats to the standard output, e.g. into jq or xsv
+const Stdout = "-"
+
+// IsStdout tells whether the path designates the standard output, which is also the default when no path is given
+func IsStdout(path string) bool {
+	return path == Stdout || path == ""
+}
+
+// Appender is implemented by outputers able to add records to an existing file, e.g. for the --append flag
+type Appender interface {
+	AppendToFile(path string) error
+}
+
+// CanAppend tells whether the given format supports appending to an existing file
+func CanAppend(format string) bool {
+	factory, err := Lookup(format)
+	if err != nil {
+		return false
+	}
+	outputer, err := factory(Records{})
+	if err != nil {
+		return false
+	}
+	_, ok := outputer.(Appender)
+	return ok
+}
+
+// WriteFileAtomic writes a file into a temporary file next to path, syncs it and renames it over path,
+// so the file is either fully replaced or, if anything fails, left as it was. The standard output is written directly.
+func WriteFileAtomic(path string, write func(w *bufio.Writer) error) error {
+	if IsStdout(path) {
+		return writeStdout(write)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	tmpPath := file.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			file.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err := writeAndSync(file, write); err != nil {
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		return fmt.Errorf("could not set file mode: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("could not close file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+	renamed = true
+	return nil
+}
+
+// AppendFile appends to the file at path, creating it if needed. write is told whether the file is empty,
+// e.g. to write headers only once. If anything fails the file is truncated back to its previous size.
+// Appending to the standard output writes a complete output.
+func AppendFile(path string, write func(w *bufio.Writer, empty bool) error) error {
+	if IsStdout(path) {
+		return writeStdout(func(w *bufio.Writer) error { return write(w, true) })
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("could not stat file: %w", err)
+	}
+	size := info.Size()
+
+	err = writeAndSync(file, func(w *bufio.Writer) error { return write(w, size == 0) })
+	if err != nil {
+		if terr := file.Truncate(size); terr != nil {
+			return fmt.Errorf("%w, and could not truncate the partially appended file: %s", err, terr)
+		}
+		return err
+	}
+	return file.Close()
+}
+
+// writeAndSync writes through a buffer, flushes it and syncs the file to disk
+func writeAndSync(file *os.File, write func(w *bufio.Writer) error) error {
+	// the buffered writer hides the file Close method, so writers closing their sink leave the file open
+	writer := bufio.NewWriter(file)
+	if err := write(writer); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("could not write file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("could not sync file: %w", err)
+	}
+	return nil
+}
+
+func writeStdout(write func(w *bufio.Writer) error) error {
+	writer := bufio.NewWriter(os.Stdout)
+	if err := write(writer); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("could not write to stdout: %w", err)
+	}
+	return nil
+}
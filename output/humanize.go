@@ -0,0 +1,125 @@
+package output
+
+import (
+	"sort"
+	"strings"
+
+	"QuicksilverDumper/usecase"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Humanize returns records with coins converted into display units, e.g. 1500000uqck into 1.5QCK: Coins columns,
+// also within lists, become DecCoins columns. Amounts in BigInt columns next to a Denom column, as in holders, become
+// DisplayAmount columns and the denom its display unit. Denoms without a display unit, or with an exponent above the
+// 18 decimals of sdk.Dec, are kept in their base denom so no amount is ever rounded, and rendered as integers by
+// FormatDisplayAmount.
+func Humanize(records Records, units map[string]usecase.DisplayUnit) Records {
+	return Records{
+		Schema: Schema{
+			Name:    records.Schema.Name + "_display",
+			Columns: humanizeColumns(records.Schema.Columns),
+			Key:     records.Schema.Key,
+		},
+		Stream: func(fn func(rows []Row) error) error {
+			return records.Stream(func(rows []Row) error {
+				for i, row := range rows {
+					rows[i] = humanizeRow(records.Schema.Columns, row, units)
+				}
+				return fn(rows)
+			})
+		},
+		Height: records.Height,
+	}
+}
+
+// denomIndex returns the index of the Denom column the BigInt columns are amounts of, -1 if there is none
+func denomIndex(columns []Column) int {
+	for i, column := range columns {
+		if column.Name == "Denom" && column.Type == String {
+			return i
+		}
+	}
+	return -1
+}
+
+func humanizeColumns(columns []Column) []Column {
+	hasDenom := denomIndex(columns) >= 0
+	humanized := make([]Column, 0, len(columns))
+	for _, column := range columns {
+		switch column.Type {
+		case Coins:
+			column.Type = DecCoins
+		case BigInt:
+			if hasDenom {
+				column.Type = DisplayAmount
+			}
+		case List:
+			column.Fields = humanizeColumns(column.Fields)
+		}
+		humanized = append(humanized, column)
+	}
+	return humanized
+}
+
+func humanizeRow(columns []Column, row Row, units map[string]usecase.DisplayUnit) Row {
+	d := denomIndex(columns)
+	var denom string
+	if d >= 0 {
+		denom, _ = row[d].(string)
+	}
+
+	humanized := make(Row, 0, len(row))
+	for i, column := range columns {
+		value := row[i]
+		switch v := value.(type) {
+		case sdk.Coins:
+			value = displayCoins(v, units)
+		case sdk.Int:
+			if d >= 0 && column.Type == BigInt {
+				value = displayCoin(sdk.Coin{Denom: denom, Amount: v}, units).Amount
+			}
+		case string:
+			if i == d {
+				value = displayCoin(sdk.Coin{Denom: denom, Amount: sdk.ZeroInt()}, units).Denom
+			}
+		case []Row:
+			elements := make([]Row, 0, len(v))
+			for _, element := range v {
+				elements = append(elements, humanizeRow(column.Fields, element, units))
+			}
+			value = elements
+		}
+		humanized = append(humanized, value)
+	}
+	return humanized
+}
+
+func displayCoins(coins sdk.Coins, units map[string]usecase.DisplayUnit) sdk.DecCoins {
+	display := make(sdk.DecCoins, 0, len(coins))
+	for _, coin := range coins {
+		display = append(display, displayCoin(coin, units))
+	}
+	sort.Slice(display, func(i, j int) bool { return display[i].Denom < display[j].Denom })
+	return display
+}
+
+// FormatDisplayAmount renders a display amount without the trailing zeros of its 18 decimals, e.g. 1.5 or 123 for an
+// amount kept in base units, as display amounts have as many decimals as their denom exponent
+func FormatDisplayAmount(amount sdk.Dec) string {
+	s := amount.String()
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+func displayCoin(coin sdk.Coin, units map[string]usecase.DisplayUnit) sdk.DecCoin {
+	unit, ok := units[coin.Denom]
+	if !ok || unit.Exponent > sdk.Precision {
+		return sdk.DecCoin{Denom: coin.Denom, Amount: sdk.NewDecFromInt(coin.Amount)}
+	}
+	return sdk.DecCoin{
+		Denom:  unit.Denom,
+		Amount: sdk.NewDecFromBigIntWithPrec(coin.Amount.BigInt(), int64(unit.Exponent)),
+	}
+}
@@ -0,0 +1,87 @@
+package jsonoutput
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"QuicksilverDumper/output"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type coin struct {
+	Denom  string      `json:"denom"`
+	Amount json.Number `json:"amount"`
+}
+
+// marshalRow renders a row as a JSON object keyed by the snake case column names, in schema order
+func marshalRow(columns []output.Column, row output.Row) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, _ := json.Marshal(column.Key())
+		b.Write(key)
+		b.WriteString(":")
+
+		value, err := MarshalValue(column, row[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", column.Name, err)
+		}
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+// MarshalValue renders a single value as JSON, it keeps amounts and shares as exact JSON numbers, sdk.Int and sdk.Dec may not fit into any Go numeric type
+func MarshalValue(column output.Column, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case sdk.Int:
+		return []byte(v.String()), nil
+	case sdk.Dec:
+		if column.Type == output.DisplayAmount {
+			return []byte(output.FormatDisplayAmount(v)), nil
+		}
+		return []byte(v.String()), nil
+	case *time.Time:
+		if v == nil {
+			return []byte("null"), nil
+		}
+		return json.Marshal(v.UTC())
+	case sdk.Coins:
+		coins := make([]coin, 0, len(v))
+		for _, c := range v {
+			coins = append(coins, coin{Denom: c.Denom, Amount: json.Number(c.Amount.String())})
+		}
+		return json.Marshal(coins)
+	case sdk.DecCoins:
+		coins := make([]coin, 0, len(v))
+		for _, c := range v {
+			coins = append(coins, coin{Denom: c.Denom, Amount: json.Number(output.FormatDisplayAmount(c.Amount))})
+		}
+		return json.Marshal(coins)
+	case []output.Row:
+		var b bytes.Buffer
+		b.WriteString("[")
+		for i, element := range v {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			e, err := marshalRow(column.Fields, element)
+			if err != nil {
+				return nil, err
+			}
+			b.Write(e)
+		}
+		b.WriteString("]")
+		return b.Bytes(), nil
+	default:
+		return json.Marshal(v)
+	}
+}
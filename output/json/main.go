@@ -0,0 +1,91 @@
+package jsonoutput
+
+import (
+	"bufio"
+	"fmt"
+
+	"QuicksilverDumper/output"
+)
+
+func init() {
+	output.Register("json", func(records output.Records) (output.Outputer, error) {
+		return NewJsonOutputer(records, false), nil
+	})
+	output.Register("jsonl", func(records output.Records) (output.Outputer, error) {
+		return JsonLinesOutputer{NewJsonOutputer(records, true)}, nil
+	})
+}
+
+type JsonOutputer struct {
+	records output.Records
+	// lines writes one record per line (JSON Lines) instead of a single JSON array
+	lines bool
+}
+
+func NewJsonOutputer(records output.Records, lines bool) JsonOutputer {
+	return JsonOutputer{records: records, lines: lines}
+}
+
+// WriteToFile replaces the file at path atomically
+func (j JsonOutputer) WriteToFile(path string) error {
+	if err := output.WriteFileAtomic(path, j.write); err != nil {
+		return fmt.Errorf("could not write to JSON file: %w", err)
+	}
+	return nil
+}
+
+// JsonLinesOutputer writes JSON Lines, which unlike a JSON array can be appended to
+type JsonLinesOutputer struct {
+	JsonOutputer
+}
+
+// AppendToFile appends one record per line to the file at path
+func (j JsonLinesOutputer) AppendToFile(path string) error {
+	err := output.AppendFile(path, func(w *bufio.Writer, _ bool) error {
+		return j.write(w)
+	})
+	if err != nil {
+		return fmt.Errorf("could not append to JSON file: %w", err)
+	}
+	return nil
+}
+
+func (j JsonOutputer) write(writer *bufio.Writer) error {
+	if !j.lines {
+		writer.WriteString("[")
+	}
+
+	count := 0
+	err := j.records.Stream(func(rows []output.Row) error {
+		for _, row := range rows {
+			b, err := marshalRow(j.records.Schema.Columns, row)
+			if err != nil {
+				return err
+			}
+			if !j.lines {
+				if count > 0 {
+					writer.WriteString(",")
+				}
+				writer.WriteString("\n  ")
+			}
+			writer.Write(b)
+			if j.lines {
+				writer.WriteString("\n")
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !j.lines {
+		if count > 0 {
+			writer.WriteString("\n")
+		}
+		writer.WriteString("]\n")
+	}
+	// bufio.Writer keeps the first write error and output.WriteFileAtomic returns it from Flush
+	return nil
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Normalize returns records with one row per denom instead of coins columns: a Denom column is added and every
+// Coins (DecCoins) column becomes a BigInt (DisplayAmount) column holding the amount of that denom, named Amount if it is the only one.
+// Rows without any coin are kept once with an empty denom, not null as it is part of the key, and null amounts.
+// List columns are left nested.
+func Normalize(records Records) Records {
+	var coinsIndexes []int
+	for i, column := range records.Schema.Columns {
+		if column.Type == Coins || column.Type == DecCoins {
+			coinsIndexes = append(coinsIndexes, i)
+		}
+	}
+	if len(coinsIndexes) == 0 {
+		return records
+	}
+
+	columns := make([]Column, 0, len(records.Schema.Columns)+1)
+	for i, column := range records.Schema.Columns {
+		if i == coinsIndexes[0] {
+			columns = append(columns, Column{Name: "Denom", Type: String})
+		}
+		if isCoinsIndex(i, coinsIndexes) {
+			if len(coinsIndexes) == 1 {
+				column.Name = "Amount"
+			}
+			if column.Type == Coins {
+				column.Type = BigInt
+			} else {
+				column.Type = DisplayAmount
+			}
+		}
+		columns = append(columns, column)
+	}
+	key := records.Schema.Key
+	if len(key) > 0 {
+		key = append(append([]string{}, key...), "Denom")
+	}
+
+	return Records{
+		Schema: Schema{Name: records.Schema.Name + "_by_denom", Columns: columns, Key: key},
+		Stream: func(fn func(rows []Row) error) error {
+			return records.Stream(func(rows []Row) error {
+				normalized := make([]Row, 0, len(rows))
+				for _, row := range rows {
+					normalized = append(normalized, normalizeRow(records.Schema.Columns, row, coinsIndexes)...)
+				}
+				return fn(normalized)
+			})
+		},
+		Height: records.Height,
+	}
+}
+
+// normalizeRow splits a row into one row per denom found in any of its coins columns, sorted by denom
+func normalizeRow(columns []Column, row Row, coinsIndexes []int) []Row {
+	denoms := map[string]bool{}
+	for _, i := range coinsIndexes {
+		switch coins := row[i].(type) {
+		case sdk.Coins:
+			for _, coin := range coins {
+				denoms[coin.Denom] = true
+			}
+		case sdk.DecCoins:
+			for _, coin := range coins {
+				denoms[coin.Denom] = true
+			}
+		}
+	}
+	sorted := make([]string, 0, len(denoms))
+	for denom := range denoms {
+		sorted = append(sorted, denom)
+	}
+	sort.Strings(sorted)
+
+	if len(sorted) == 0 {
+		return []Row{expandRow(columns, row, coinsIndexes, nil)}
+	}
+	rows := make([]Row, 0, len(sorted))
+	for _, denom := range sorted {
+		denom := denom
+		rows = append(rows, expandRow(columns, row, coinsIndexes, &denom))
+	}
+	return rows
+}
+
+// expandRow copies the row with the denom inserted before the first coins column and coins replaced by the denom
+// amount, zero when the column holds other denoms only. A nil denom gives an empty denom and null amounts.
+func expandRow(columns []Column, row Row, coinsIndexes []int, denom *string) Row {
+	expanded := make(Row, 0, len(row)+1)
+	for i, value := range row {
+		if i == coinsIndexes[0] {
+			if denom == nil {
+				expanded = append(expanded, "")
+			} else {
+				expanded = append(expanded, *denom)
+			}
+		}
+		if isCoinsIndex(i, coinsIndexes) {
+			if denom == nil {
+				expanded = append(expanded, nil)
+			} else {
+				expanded = append(expanded, amountOf(columns[i].Type, value, *denom))
+			}
+			continue
+		}
+		expanded = append(expanded, value)
+	}
+	return expanded
+}
+
+func isCoinsIndex(i int, coinsIndexes []int) bool {
+	for _, index := range coinsIndexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+// amountOf is the AmountOf of coins without the denom validation, which panics on denoms not matching the local regex.
+// Null coins have a zero amount.
+func amountOf(columnType ColumnType, value interface{}, denom string) interface{} {
+	if columnType == DecCoins {
+		coins, _ := value.(sdk.DecCoins)
+		for _, coin := range coins {
+			if coin.Denom == denom {
+				return coin.Amount
+			}
+		}
+		return sdk.ZeroDec()
+	}
+	coins, _ := value.(sdk.Coins)
+	for _, coin := range coins {
+		if coin.Denom == denom {
+			return coin.Amount
+		}
+	}
+	return sdk.ZeroInt()
+}
@@ -1,13 +1,62 @@
 package output
 
 import (
-	csvoutput "QuicksilverDumper/output/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 )
 
 type Outputer interface {
 	WriteToFile(path string) error
 }
 
-func GetCSVOutputer(v csvoutput.CsvConvertable) (Outputer, error) {
-	return csvoutput.NewCsvOutputer(v)
+// Factory creates an Outputer writing the given records in a specific format
+type Factory func(records Records) (Outputer, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes an output format available by name, it is meant to be called from the init function of the format package
+func Register(format string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[format]; ok {
+		panic(fmt.Sprintf("output format %s registered twice", format))
+	}
+	registry[format] = factory
+}
+
+// Formats returns the names of all registered formats, sorted
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	formats := make([]string, 0, len(registry))
+	for format := range registry {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Lookup returns the factory of the given format, or an error listing the supported formats
+func Lookup(format string) (Factory, error) {
+	registryMu.RLock()
+	factory, ok := registry[format]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, supported formats: %s", format, strings.Join(Formats(), ", "))
+	}
+	return factory, nil
+}
+
+// GetOutputer returns an Outputer writing the records in the given format
+func GetOutputer(format string, records Records) (Outputer, error) {
+	factory, err := Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+	return factory(records)
 }
@@ -0,0 +1,74 @@
+package parquetoutput
+
+import (
+	"bufio"
+	"fmt"
+
+	"QuicksilverDumper/output"
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/compress"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+)
+
+func init() {
+	output.Register("parquet", func(records output.Records) (output.Outputer, error) {
+		return NewParquetOutputer(records), nil
+	})
+}
+
+type ParquetOutputer struct {
+	records output.Records
+}
+
+func NewParquetOutputer(records output.Records) ParquetOutputer {
+	return ParquetOutputer{records: records}
+}
+
+func (p ParquetOutputer) WriteToFile(path string) error {
+	schema := arrowSchema(p.records.Schema)
+
+	err := output.WriteFileAtomic(path, func(w *bufio.Writer) error {
+		return p.write(schema, w)
+	})
+	if err != nil {
+		return fmt.Errorf("could not write to parquet file: %w", err)
+	}
+	return nil
+}
+
+func (p ParquetOutputer) write(schema *arrow.Schema, w *bufio.Writer) error {
+	writer, err := pqarrow.NewFileWriter(schema, w,
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy)),
+		pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create parquet writer: %w", err)
+	}
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	err = p.records.Stream(func(rows []output.Row) error {
+		for _, row := range rows {
+			for i, column := range p.records.Schema.Columns {
+				if err := appendValue(builder.Field(i), column, row[i]); err != nil {
+					return fmt.Errorf("column %s: %w", column.Name, err)
+				}
+			}
+		}
+		record := builder.NewRecord()
+		defer record.Release()
+		// buffered writes gather small batches into row groups of the default size
+		return writer.WriteBuffered(record)
+	})
+	if err != nil {
+		writer.Close()
+		return err
+	}
+
+	// closing the writer writes the footer, the file itself is synced and closed by output.WriteFileAtomic
+	return writer.Close()
+}
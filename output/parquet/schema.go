@@ -0,0 +1,140 @@
+package parquetoutput
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"QuicksilverDumper/output"
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/decimal128"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Amounts are stored as decimal(38, 0), i.e. int128, and shares as decimal(38, 18), the widest decimals DuckDB
+// and Spark read natively. Values that do not fit are rejected. Display amounts have as many decimals as their
+// denom exponent, up to 18, or none when kept in base units, which no single decimal type holds: they are exact
+// decimal strings.
+var (
+	amountType  = &arrow.Decimal128Type{Precision: 38, Scale: 0}
+	decimalType = &arrow.Decimal128Type{Precision: 38, Scale: sdk.Precision}
+	timeType    = &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	coinType    = arrow.StructOf(
+		arrow.Field{Name: "denom", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "amount", Type: amountType},
+	)
+	decCoinType = arrow.StructOf(
+		arrow.Field{Name: "denom", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "amount", Type: arrow.BinaryTypes.String},
+	)
+)
+
+func arrowSchema(schema output.Schema) *arrow.Schema {
+	return arrow.NewSchema(arrowFields(schema.Columns), nil)
+}
+
+func arrowFields(columns []output.Column) []arrow.Field {
+	fields := make([]arrow.Field, 0, len(columns))
+	for _, column := range columns {
+		fields = append(fields, arrow.Field{Name: column.Key(), Type: arrowType(column), Nullable: true})
+	}
+	return fields
+}
+
+func arrowType(column output.Column) arrow.DataType {
+	switch column.Type {
+	case output.Int:
+		return arrow.PrimitiveTypes.Int64
+	case output.BigInt:
+		return amountType
+	case output.Decimal:
+		return decimalType
+	case output.Time:
+		return timeType
+	case output.Coins:
+		return arrow.ListOf(coinType)
+	case output.DecCoins:
+		return arrow.ListOf(decCoinType)
+	case output.List:
+		return arrow.ListOf(arrow.StructOf(arrowFields(column.Fields)...))
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func appendValue(builder array.Builder, column output.Column, value interface{}) error {
+	if value == nil {
+		builder.AppendNull()
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		builder.(*array.StringBuilder).Append(v)
+	case int64:
+		builder.(*array.Int64Builder).Append(v)
+	case sdk.Int:
+		return appendDecimal(builder, v.BigInt())
+	case sdk.Dec:
+		if column.Type == output.DisplayAmount {
+			builder.(*array.StringBuilder).Append(output.FormatDisplayAmount(v))
+			return nil
+		}
+		// sdk.Dec holds its value as an integer scaled by 10^18, the scale of decimalType
+		return appendDecimal(builder, v.BigInt())
+	case *time.Time:
+		if v == nil {
+			builder.AppendNull()
+			return nil
+		}
+		builder.(*array.TimestampBuilder).Append(arrow.Timestamp(v.UnixMicro()))
+	case sdk.Coins:
+		list := builder.(*array.ListBuilder)
+		list.Append(true)
+		coins := list.ValueBuilder().(*array.StructBuilder)
+		for _, coin := range v {
+			coins.Append(true)
+			coins.FieldBuilder(0).(*array.StringBuilder).Append(coin.Denom)
+			if err := appendDecimal(coins.FieldBuilder(1), coin.Amount.BigInt()); err != nil {
+				return err
+			}
+		}
+	case sdk.DecCoins:
+		list := builder.(*array.ListBuilder)
+		list.Append(true)
+		coins := list.ValueBuilder().(*array.StructBuilder)
+		for _, coin := range v {
+			coins.Append(true)
+			coins.FieldBuilder(0).(*array.StringBuilder).Append(coin.Denom)
+			coins.FieldBuilder(1).(*array.StringBuilder).Append(output.FormatDisplayAmount(coin.Amount))
+		}
+	case []output.Row:
+		list := builder.(*array.ListBuilder)
+		list.Append(true)
+		elements := list.ValueBuilder().(*array.StructBuilder)
+		for _, element := range v {
+			elements.Append(true)
+			for i, field := range column.Fields {
+				if err := appendValue(elements.FieldBuilder(i), field, element[i]); err != nil {
+					return fmt.Errorf("%s: %w", field.Name, err)
+				}
+			}
+		}
+	default:
+		builder.(*array.StringBuilder).Append(fmt.Sprint(v))
+	}
+	return nil
+}
+
+func appendDecimal(builder array.Builder, v *big.Int) error {
+	if v.BitLen() > 127 {
+		return fmt.Errorf("%s does not fit into decimal(38)", v)
+	}
+	n := decimal128.FromBigInt(v)
+	if !n.FitsInPrecision(38) {
+		return fmt.Errorf("%s does not fit into decimal(38)", v)
+	}
+	builder.(*array.Decimal128Builder).Append(n)
+	return nil
+}
@@ -0,0 +1,87 @@
+package postgresoutput
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"QuicksilverDumper/output"
+	sqloutput "QuicksilverDumper/output/sql"
+)
+
+// migrate creates the records table with the schema key as primary key, and adds the columns the table misses.
+// It runs in its own short transaction, as ALTER TABLE locks the table against readers until the transaction ends,
+// and only alters the table when a column is actually missing.
+func migrate(db *sql.DB, schema output.Schema) error {
+	definitions := make([]string, 0, len(schema.Columns)+1)
+	for _, column := range schema.Columns {
+		definitions = append(definitions, fmt.Sprintf("%s %s", sqloutput.Quote(column.Key()), sqlType(column)))
+	}
+	key := make([]string, 0, len(schema.Key))
+	for _, name := range schema.Key {
+		key = append(key, sqloutput.Quote(output.SnakeCase(name)))
+	}
+	definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(key, ", ")))
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	table := sqloutput.Quote(schema.Name)
+	_, err = tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", table, strings.Join(definitions, ", ")))
+	if err != nil {
+		return fmt.Errorf("failed to create table %s: %w", schema.Name, err)
+	}
+
+	existing, err := columns(tx, schema.Name)
+	if err != nil {
+		return err
+	}
+	for _, column := range schema.Columns {
+		if existing[column.Key()] {
+			continue
+		}
+		_, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, sqloutput.Quote(column.Key()), sqlType(column)))
+		if err != nil {
+			return fmt.Errorf("failed to add column %s to table %s: %w", column.Key(), schema.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// columns returns the names of the columns of the table in the current schema
+func columns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query("SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns of table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to get columns of table %s: %w", table, err)
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// sqlType maps column types to Postgres types, amounts and shares are exact NUMERIC, coins and lists are JSONB
+func sqlType(column output.Column) string {
+	switch column.Type {
+	case output.Int:
+		return "BIGINT"
+	case output.BigInt, output.Decimal, output.DisplayAmount:
+		return "NUMERIC"
+	case output.Time:
+		return "TIMESTAMPTZ"
+	case output.Coins, output.DecCoins, output.List:
+		return "JSONB"
+	default:
+		return "TEXT"
+	}
+}
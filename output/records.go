@@ -0,0 +1,103 @@
+package output
+
+import (
+	"strings"
+	"unicode"
+)
+
+type ColumnType int
+
+// Go types of the row values for every column type, a nil value is a null
+const (
+	String        ColumnType = iota // string
+	Int                             // int64
+	BigInt                          // sdk.Int
+	Decimal                         // sdk.Dec
+	Time                            // *time.Time
+	Coins                           // sdk.Coins
+	DecCoins                        // sdk.DecCoins, coins converted into display units
+	DisplayAmount                   // sdk.Dec, an amount converted into display units
+	List                            // []Row, every row holding the values of the column Fields
+)
+
+type Column struct {
+	Name string
+	Type ColumnType
+	// Fields describe the elements of a List column
+	Fields []Column
+}
+
+// Key returns the column name in snake case, used where CamelCase names are unusual, e.g. JSON keys and SQL columns
+func (c Column) Key() string {
+	return SnakeCase(c.Name)
+}
+
+type Schema struct {
+	// Name identifies the result in snake case, e.g. a table name for database outputs
+	Name    string
+	Columns []Column
+	// Key names the columns identifying a row across runs, e.g. the primary key for upserting outputs
+	Key []string
+}
+
+// Row holds one value per schema column, in the same order
+type Row []interface{}
+
+// Records is a format-neutral result: a schema and the rows, produced in batches
+// so outputers can write them before all of them are known
+type Records struct {
+	Schema Schema
+	Stream func(fn func(rows []Row) error) error
+	// Height is the block height the data was queried at, 0 if unknown
+	Height int64
+}
+
+// StaticRecords returns records whose rows are all already known
+func StaticRecords(schema Schema, rows []Row) Records {
+	return Records{
+		Schema: schema,
+		Stream: func(fn func(rows []Row) error) error {
+			return fn(rows)
+		},
+	}
+}
+
+// WithHeight appends the block height the data was queried at as the last column of every row
+func WithHeight(records Records, height int64) Records {
+	columns := make([]Column, 0, len(records.Schema.Columns)+1)
+	columns = append(columns, records.Schema.Columns...)
+	columns = append(columns, Column{Name: "Height", Type: Int})
+
+	return Records{
+		Schema: Schema{Name: records.Schema.Name, Columns: columns, Key: records.Schema.Key},
+		Stream: func(fn func(rows []Row) error) error {
+			return records.Stream(func(rows []Row) error {
+				for i := range rows {
+					rows[i] = append(rows[i], height)
+				}
+				return fn(rows)
+			})
+		},
+		Height: height,
+	}
+}
+
+// SnakeCase converts names like "ChainId" or "Account Type" into "chain_id" and "account_type"
+func SnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteRune('_')
+		case unicode.IsUpper(r):
+			if i > 0 && runes[i-1] != ' ' && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package sqloutput
+
+import (
+	"strings"
+	"time"
+
+	"QuicksilverDumper/output"
+	jsonoutput "QuicksilverDumper/output/json"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Value converts a row value into a database/sql argument shared by the SQL outputs: amounts and shares are exact
+// decimal strings, times are UTC time.Time, coins and lists are JSON strings.
+func Value(column output.Column, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string, int64:
+		return v, nil
+	case sdk.Int:
+		return v.String(), nil
+	case sdk.Dec:
+		if column.Type == output.DisplayAmount {
+			return output.FormatDisplayAmount(v), nil
+		}
+		return v.String(), nil
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		return v.UTC(), nil
+	default:
+		b, err := jsonoutput.MarshalValue(column, value)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+}
+
+// Quote quotes an identifier, e.g. a table or column name
+func Quote(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
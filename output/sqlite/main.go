@@ -0,0 +1,110 @@
+package sqliteoutput
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"QuicksilverDumper/output"
+	sqloutput "QuicksilverDumper/output/sql"
+	_ "modernc.org/sqlite"
+)
+
+func init() {
+	output.Register("sqlite", func(records output.Records) (output.Outputer, error) {
+		return NewSqliteOutputer(records), nil
+	})
+}
+
+// SqliteOutputer appends the records into the table named after the records schema of a SQLite database.
+// Every write is a run recorded in the runs table, each row references its run by the run_id column.
+type SqliteOutputer struct {
+	records output.Records
+}
+
+func NewSqliteOutputer(records output.Records) SqliteOutputer {
+	return SqliteOutputer{records: records}
+}
+
+func (s SqliteOutputer) WriteToFile(path string) error {
+	if s.records.Schema.Name == "" {
+		return fmt.Errorf("records have no name to use as table name")
+	}
+	if output.IsStdout(path) {
+		return fmt.Errorf("sqlite output can not be written to stdout, set --output to a database file")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("could not open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	if err := migrate(db, s.records.Schema); err != nil {
+		return fmt.Errorf("could not migrate SQLite database: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.insert(tx); err != nil {
+		return fmt.Errorf("could not write to SQLite database: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s SqliteOutputer) insert(tx *sql.Tx) error {
+	res, err := tx.Exec(`INSERT INTO runs (task, height, created_at) VALUES (?, ?, ?)`,
+		s.records.Schema.Name, s.records.Height, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("failed to insert run: %w", err)
+	}
+	runId, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get run id: %w", err)
+	}
+
+	columns := s.records.Schema.Columns
+	names := []string{"run_id"}
+	placeholders := []string{"?"}
+	for _, column := range columns {
+		names = append(names, sqloutput.Quote(column.Key()))
+		placeholders = append(placeholders, "?")
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		sqloutput.Quote(s.records.Schema.Name), strings.Join(names, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	return s.records.Stream(func(rows []output.Row) error {
+		for _, row := range rows {
+			args := make([]interface{}, 0, len(row)+1)
+			args = append(args, runId)
+			for i, column := range columns {
+				value, err := sqlValue(column, row[i])
+				if err != nil {
+					return fmt.Errorf("column %s: %w", column.Name, err)
+				}
+				args = append(args, value)
+			}
+			if _, err := stmt.Exec(args...); err != nil {
+				return fmt.Errorf("failed to insert row: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package sqliteoutput
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"QuicksilverDumper/output"
+	sqloutput "QuicksilverDumper/output/sql"
+)
+
+// migrate creates the runs table and the records table, and adds the columns the records table misses
+func migrate(db *sql.DB, schema output.Schema) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task TEXT NOT NULL,
+		height INTEGER NOT NULL,
+		created_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create runs table: %w", err)
+	}
+
+	table := sqloutput.Quote(schema.Name)
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (run_id INTEGER NOT NULL REFERENCES runs (id))", table))
+	if err != nil {
+		return fmt.Errorf("failed to create table %s: %w", schema.Name, err)
+	}
+
+	existing, err := tableColumns(db, schema.Name)
+	if err != nil {
+		return err
+	}
+	for _, column := range schema.Columns {
+		if existing[column.Key()] {
+			continue
+		}
+		_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, sqloutput.Quote(column.Key()), sqlType(column)))
+		if err != nil {
+			return fmt.Errorf("failed to add column %s to table %s: %w", column.Key(), schema.Name, err)
+		}
+	}
+
+	index := []string{"run_id"}
+	if existing["height"] || hasColumn(schema, "height") {
+		index = append(index, "height")
+	}
+	_, err = db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+		sqloutput.Quote(schema.Name+"_run"), table, strings.Join(index, ", ")))
+	if err != nil {
+		return fmt.Errorf("failed to create index on table %s: %w", schema.Name, err)
+	}
+	return nil
+}
+
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns of table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+func hasColumn(schema output.Schema, key string) bool {
+	for _, column := range schema.Columns {
+		if column.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlType maps column types to SQLite types. Amounts and shares are kept as exact TEXT since SQLite integers
+// are 64 bit, times are RFC3339 TEXT understood by SQLite date functions, coins and lists are JSON TEXT.
+func sqlType(column output.Column) string {
+	if column.Type == output.Int {
+		return "INTEGER"
+	}
+	return "TEXT"
+}
+
+// sqlValue stores times as RFC3339 TEXT understood by SQLite date functions
+func sqlValue(column output.Column, value interface{}) (interface{}, error) {
+	v, err := sqloutput.Value(column, value)
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano), err
+	}
+	return v, err
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec/types"
+)
+
+// StreamAllAccounts calls fn with the decoded accounts of every accounts page as soon as the page arrives
+func (uc *UseCase) StreamAllAccounts(ctx context.Context, fn func([]*Account) error) error {
+	uc.Logger.Infof("Streaming all accounts")
+	count := 0
+	err := uc.Cli.StreamAllAccounts(ctx, func(anys []*types.Any) error {
+		accounts := make([]*Account, 0, len(anys))
+		for _, any := range anys {
+			account, err := AccountFromProtoAny(any)
+			if err != nil {
+				uc.Logger.Errorf("Failed to decode account: %e", err.Error())
+				return fmt.Errorf("failed to decode %s account: %w", any.TypeUrl, err)
+			}
+			if account.Address == "" {
+				// the address identifies accounts, e.g. as key of database outputs
+				uc.Logger.Infof("Skipping %s account without a decodable address", any.TypeUrl)
+				continue
+			}
+			accounts = append(accounts, account)
+		}
+		count += len(accounts)
+		return fn(accounts)
+	})
+	if err != nil {
+		uc.Logger.Errorf("Failed to stream all accounts: %e", err.Error())
+		return err
+	}
+
+	uc.Logger.Infof(fmt.Sprintf("Found %d accounts", count))
+	return nil
+}
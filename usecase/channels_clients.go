@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	ibcConnection "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+)
+
+// GetChannelsClientStates gets the statuses of all IBC channels matching the given filter together with their connections and light clients
+func (uc *UseCase) GetChannelsClientStates(ctx context.Context, filter ChannelsFilter) ([]*ChannelClientState, error) {
+	channels, err := uc.getChannelsStatuses(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	connections := map[string]*ibcConnection.ConnectionEnd{}
+	clients := map[string]*LightClientState{}
+
+	result := make([]*ChannelClientState, 0, len(channels))
+	for _, channel := range channels {
+		connection, ok := connections[channel.ConnectionId]
+		if !ok {
+			uc.Logger.Infof(fmt.Sprintf("Getting connection %s", channel.ConnectionId))
+			connection, err = uc.Cli.GetIBCConnection(ctx, channel.ConnectionId)
+			if err != nil {
+				uc.Logger.Errorf("Failed to get connection: %e", err.Error())
+				return nil, err
+			}
+			connections[channel.ConnectionId] = connection
+		}
+
+		client, ok := clients[connection.ClientId]
+		if !ok {
+			uc.Logger.Infof(fmt.Sprintf("Getting client state %s", connection.ClientId))
+			client, err = uc.getLightClientState(ctx, connection.ClientId)
+			if err != nil {
+				uc.Logger.Errorf("Failed to get client state: %e", err.Error())
+				return nil, err
+			}
+			clients[connection.ClientId] = client
+		}
+
+		if filter.CounterpartyChainId != "" && client.ChainId != filter.CounterpartyChainId {
+			continue
+		}
+
+		result = append(result, &ChannelClientState{
+			ChannelStatus:       *channel,
+			ConnectionState:     connection.State.String(),
+			ClientId:            connection.ClientId,
+			ClientType:          client.ClientType,
+			CounterpartyChainId: client.ChainId,
+			LatestHeight:        client.LatestHeight,
+			TrustingPeriod:      client.TrustingPeriod,
+			FrozenHeight:        client.FrozenHeight,
+			Status:              client.Status,
+		})
+	}
+
+	uc.Logger.Infof(fmt.Sprintf("Resolved %d clients for %d channels", len(clients), len(result)))
+	return result, nil
+}
+
+// getLightClientState gets the light client state and its status for the given client
+func (uc *UseCase) getLightClientState(ctx context.Context, clientId string) (*LightClientState, error) {
+	anyState, err := uc.Cli.GetIBCClientState(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+	status, err := uc.Cli.GetIBCClientStatus(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := LightClientStateFromProtoAny(anyState)
+	if err != nil {
+		if err != UnknownClientState {
+			return nil, err
+		}
+		state = &LightClientState{ClientType: anyState.TypeUrl}
+	}
+	state.Status = status
+	return state, nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GetAllDelegatorsAndValidators gets all delegators and the validators they delegate to
+func (uc *UseCase) GetAllDelegatorsAndValidators(ctx context.Context) ([]DelegatorWithValidators, error) {
+	validators, err := uc.GetAllValidatorsAndDelegators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.Logger.Infof("Grouping delegations by delegator")
+	delegators := groupByDelegator(validators)
+
+	uc.Logger.Infof(fmt.Sprintf("Found %d delegators with validators", len(delegators)))
+	return delegators, nil
+}
+
+// groupByDelegator pivots validator-keyed rows into delegator-keyed rows sorted by delegator address
+func groupByDelegator(validators []ValidatorWithDelegators) []DelegatorWithValidators {
+	byDelegator := map[string]*DelegatorWithValidators{}
+	for _, vwd := range validators {
+		dwv, ok := byDelegator[vwd.DelegatorAddress]
+		if !ok {
+			dwv = &DelegatorWithValidators{
+				DelegatorAddress: vwd.DelegatorAddress,
+				TotalDelegations: sdk.Coins{},
+			}
+			byDelegator[vwd.DelegatorAddress] = dwv
+		}
+
+		dwv.Validators = append(dwv.Validators, ValidatorDelegation{
+			ValidatorAddress: vwd.ValidatorAddress,
+			Delegations:      vwd.Delegations,
+			Shares:           vwd.TotalShares,
+		})
+		dwv.TotalDelegations = dwv.TotalDelegations.Add(vwd.Delegations...)
+	}
+
+	delegators := make([]DelegatorWithValidators, 0, len(byDelegator))
+	for _, dwv := range byDelegator {
+		sort.Slice(dwv.Validators, func(i, j int) bool {
+			return dwv.Validators[i].ValidatorAddress < dwv.Validators[j].ValidatorAddress
+		})
+		delegators = append(delegators, *dwv)
+	}
+	sort.Slice(delegators, func(i, j int) bool {
+		return delegators[i].DelegatorAddress < delegators[j].DelegatorAddress
+	})
+	return delegators
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// DisplayUnit is the unit a base denom is shown in, e.g. QCK with exponent 6 for uqck
+type DisplayUnit struct {
+	Denom    string
+	Exponent uint32
+}
+
+// GetDisplayUnits gets the display unit of every base denom having bank metadata, keyed by base denom.
+// The symbol is preferred over the display denom, e.g. QCK over qck.
+func (uc *UseCase) GetDisplayUnits(ctx context.Context) (map[string]DisplayUnit, error) {
+	uc.Logger.Infof("Getting all denoms metadata")
+	metadatas, err := uc.Cli.GetAllDenomsMetadata(ctx)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get all denoms metadata: %e", err.Error())
+		return nil, fmt.Errorf("failed to get all denoms metadata: %w", err)
+	}
+
+	units := make(map[string]DisplayUnit, len(metadatas))
+	for _, metadata := range metadatas {
+		unit, ok := displayUnit(metadata)
+		if ok {
+			units[metadata.Base] = unit
+		}
+	}
+	uc.Logger.Infof(fmt.Sprintf("Found display units for %d denoms", len(units)))
+	return units, nil
+}
+
+func displayUnit(metadata banktypes.Metadata) (DisplayUnit, bool) {
+	if metadata.Base == "" || metadata.Display == "" || metadata.Display == metadata.Base {
+		return DisplayUnit{}, false
+	}
+	for _, unit := range metadata.DenomUnits {
+		if unit.Denom != metadata.Display {
+			continue
+		}
+		denom := metadata.Symbol
+		if denom == "" {
+			denom = strings.ToUpper(metadata.Display)
+		}
+		return DisplayUnit{Denom: denom, Exponent: unit.Exponent}, true
+	}
+	return DisplayUnit{}, false
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"QuicksilverDumper/workerpool"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+type stakedAndUnbonding struct {
+	staked    map[string]sdk.Int
+	unbonding map[string]sdk.Int
+}
+
+// GetHolders gets every address holding the denom, the bond denom if empty, ranked by decreasing total. For the bond
+// denom the staked and unbonding amounts of every delegator are added, and the staking pools holding them are left out.
+func (uc *UseCase) GetHolders(ctx context.Context, denom string) (*HoldersReport, error) {
+	bondDenom, err := uc.Cli.GetBondDenom(ctx)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get bond denom: %e", err.Error())
+		return nil, fmt.Errorf("failed to get bond denom: %w", err)
+	}
+	if denom == "" {
+		denom = bondDenom
+	}
+
+	uc.Logger.Infof(fmt.Sprintf("Getting supply of %s", denom))
+	supply, err := uc.Cli.GetSupplyOf(ctx, denom)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get supply: %e", err.Error())
+		return nil, fmt.Errorf("failed to get supply of %s: %w", denom, err)
+	}
+
+	excluded := map[string]bool{}
+	stake := stakedAndUnbonding{staked: map[string]sdk.Int{}, unbonding: map[string]sdk.Int{}}
+	if denom == bondDenom {
+		for _, pool := range []string{stakingtypes.BondedPoolName, stakingtypes.NotBondedPoolName} {
+			address, err := uc.Cli.GetModuleAccountAddress(ctx, pool)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s address: %w", pool, err)
+			}
+			excluded[address] = true
+		}
+		if stake, err = uc.getStakedAndUnbonding(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.Logger.Infof(fmt.Sprintf("Streaming owners of %s", denom))
+	liquid := map[string]sdk.Int{}
+	err = uc.Cli.StreamDenomOwners(ctx, denom, func(owners []*banktypes.DenomOwner) error {
+		for _, owner := range owners {
+			if !excluded[owner.Address] {
+				liquid[owner.Address] = owner.Balance.Amount
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		uc.Logger.Errorf("Failed to stream denom owners: %e", err.Error())
+		return nil, fmt.Errorf("failed to get owners of %s: %w", denom, err)
+	}
+
+	report := &HoldersReport{Denom: denom, Supply: supply.Amount}
+	report.Holders = rankHolders(liquid, stake, supply.Amount)
+	report.Gini = gini(report.Holders)
+
+	uc.Logger.Infof(fmt.Sprintf("Found %d holders of %s", len(report.Holders), denom))
+	return report, nil
+}
+
+// getStakedAndUnbonding sums the delegations and unbonding delegations of every delegator over all validators
+func (uc *UseCase) getStakedAndUnbonding(ctx context.Context) (stakedAndUnbonding, error) {
+	stake := stakedAndUnbonding{staked: map[string]sdk.Int{}, unbonding: map[string]sdk.Int{}}
+
+	uc.Logger.Infof("Getting all validators")
+	validators, err := uc.Cli.GetAllValidators(ctx)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get all validators: %e", err.Error())
+		return stake, fmt.Errorf("failed to get all validators: %w", err)
+	}
+
+	err = workerpool.MapOrdered(ctx, uc.Concurrency, validators,
+		func(ctx context.Context, validator stakingtypes.Validator) (stakedAndUnbonding, error) {
+			uc.Logger.Infof(fmt.Sprintf("Getting delegations and unbondings for validator: %s", validator.OperatorAddress))
+			validatorStake := stakedAndUnbonding{staked: map[string]sdk.Int{}, unbonding: map[string]sdk.Int{}}
+			err := uc.Cli.StreamValidatorDelegations(ctx, validator.OperatorAddress, func(delegations stakingtypes.DelegationResponses) error {
+				for _, delegation := range delegations {
+					addAmount(validatorStake.staked, delegation.Delegation.DelegatorAddress, delegation.Balance.Amount)
+				}
+				return nil
+			})
+			if err != nil {
+				return validatorStake, fmt.Errorf("failed to get delegations for validator %s: %w", validator.OperatorAddress, err)
+			}
+
+			unbondings, err := uc.Cli.GetValidatorUnbondingDelegations(ctx, validator.OperatorAddress)
+			if err != nil {
+				return validatorStake, fmt.Errorf("failed to get unbonding delegations for validator %s: %w", validator.OperatorAddress, err)
+			}
+			for _, ubd := range unbondings {
+				for _, entry := range ubd.Entries {
+					addAmount(validatorStake.unbonding, ubd.DelegatorAddress, entry.Balance)
+				}
+			}
+			return validatorStake, nil
+		},
+		func(validatorStake stakedAndUnbonding) error {
+			for address, amount := range validatorStake.staked {
+				addAmount(stake.staked, address, amount)
+			}
+			for address, amount := range validatorStake.unbonding {
+				addAmount(stake.unbonding, address, amount)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get validator delegations: %e", err.Error())
+		return stake, err
+	}
+	return stake, nil
+}
+
+func addAmount(amounts map[string]sdk.Int, address string, amount sdk.Int) {
+	if current, ok := amounts[address]; ok {
+		amount = current.Add(amount)
+	}
+	amounts[address] = amount
+}
+
+// rankHolders joins the amounts per address, drops empty holders and ranks them by decreasing total, then address
+func rankHolders(liquid map[string]sdk.Int, stake stakedAndUnbonding, supply sdk.Int) []Holder {
+	totals := map[string]*Holder{}
+	holder := func(address string) *Holder {
+		h, ok := totals[address]
+		if !ok {
+			h = &Holder{Address: address, Liquid: sdk.ZeroInt(), Staked: sdk.ZeroInt(), Unbonding: sdk.ZeroInt()}
+			totals[address] = h
+		}
+		return h
+	}
+	for address, amount := range liquid {
+		holder(address).Liquid = amount
+	}
+	for address, amount := range stake.staked {
+		holder(address).Staked = amount
+	}
+	for address, amount := range stake.unbonding {
+		holder(address).Unbonding = amount
+	}
+
+	holders := make([]Holder, 0, len(totals))
+	for _, h := range totals {
+		h.Total = h.Liquid.Add(h.Staked).Add(h.Unbonding)
+		if h.Total.IsPositive() {
+			holders = append(holders, *h)
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if !holders[i].Total.Equal(holders[j].Total) {
+			return holders[i].Total.GT(holders[j].Total)
+		}
+		return holders[i].Address < holders[j].Address
+	})
+
+	cumulative := sdk.ZeroInt()
+	for i := range holders {
+		cumulative = cumulative.Add(holders[i].Total)
+		holders[i].Rank = i + 1
+		holders[i].Share = share(holders[i].Total, supply)
+		holders[i].CumulativeShare = share(cumulative, supply)
+	}
+	return holders
+}
+
+func share(amount, supply sdk.Int) sdk.Dec {
+	if !supply.IsPositive() {
+		return sdk.ZeroDec()
+	}
+	return sdk.NewDecFromInt(amount).QuoInt(supply)
+}
+
+// gini computes the Gini coefficient of holders ranked by decreasing total:
+// G = 2 * sum(i * x_i) / (n * sum(x_i)) - (n + 1) / n, with x sorted increasingly and i starting at 1
+func gini(holders []Holder) sdk.Dec {
+	n := int64(len(holders))
+	if n == 0 {
+		return sdk.ZeroDec()
+	}
+	weighted := sdk.ZeroInt()
+	sum := sdk.ZeroInt()
+	for i, h := range holders {
+		// holders are ranked decreasingly, so the increasing rank of holders[i] is n - i
+		weighted = weighted.Add(h.Total.MulRaw(n - int64(i)))
+		sum = sum.Add(h.Total)
+	}
+	return sdk.NewDecFromInt(weighted.MulRaw(2)).QuoInt(sum.MulRaw(n)).Sub(sdk.NewDec(n + 1).QuoInt64(n))
+}
@@ -2,11 +2,19 @@ package usecase
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
 	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	icatypes "github.com/cosmos/ibc-go/v5/modules/apps/27-interchain-accounts/types"
 	ibcCore "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	ibctm "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 type ValidatorWithDelegators struct {
@@ -16,21 +24,112 @@ type ValidatorWithDelegators struct {
 	TotalShares      sdk.Dec
 }
 
+type ValidatorDelegation struct {
+	ValidatorAddress string
+	Delegations      sdk.Coins
+	Shares           sdk.Dec
+}
+
+type DelegatorWithValidators struct {
+	DelegatorAddress string
+	Validators       []ValidatorDelegation
+	TotalDelegations sdk.Coins
+}
+
+// UnbondingEntry is an entry of an unbonding delegation, Entry is its index among the entries created at the same height
+type UnbondingEntry struct {
+	DelegatorAddress string
+	ValidatorAddress string
+	Entry            int
+	CreationHeight   int64
+	CompletionTime   time.Time
+	InitialBalance   sdk.Coin
+	Balance          sdk.Coin
+}
+
+// RedelegationEntry is an entry of a redelegation, Entry is its index among the entries created at the same height
+type RedelegationEntry struct {
+	DelegatorAddress    string
+	SrcValidatorAddress string
+	DstValidatorAddress string
+	Entry               int
+	CreationHeight      int64
+	CompletionTime      time.Time
+	InitialBalance      sdk.Coin
+	Balance             sdk.Coin
+	SharesDst           sdk.Dec
+}
+
 type ChannelStatus struct {
 	SourceChannelId       string
 	SourcePortId          string
 	CounterpartyChannelId string
 	CounterpartyPortId    string
 	State                 string
+	ConnectionId          string
 }
 
 func ChannelStatusFromIdentifiedChannel(channel *ibcCore.IdentifiedChannel) *ChannelStatus {
+	connectionId := ""
+	if len(channel.ConnectionHops) > 0 {
+		connectionId = channel.ConnectionHops[0]
+	}
 	return &ChannelStatus{
 		SourceChannelId:       channel.ChannelId,
 		SourcePortId:          channel.PortId,
 		CounterpartyChannelId: channel.Counterparty.ChannelId,
 		CounterpartyPortId:    channel.Counterparty.PortId,
 		State:                 channel.State.String(),
+		ConnectionId:          connectionId,
+	}
+}
+
+// ChannelsFilter restricts channels to the given counterparty chain and source port prefix, empty fields match everything
+type ChannelsFilter struct {
+	CounterpartyChainId string
+	PortPrefix          string
+}
+
+type ChannelClientState struct {
+	ChannelStatus
+	ConnectionState     string
+	ClientId            string
+	ClientType          string
+	CounterpartyChainId string
+	LatestHeight        string
+	TrustingPeriod      time.Duration
+	FrozenHeight        string
+	Status              string
+}
+
+// LightClientState is the subset of a light client state reported alongside channels
+type LightClientState struct {
+	ClientType     string
+	ChainId        string
+	LatestHeight   string
+	TrustingPeriod time.Duration
+	FrozenHeight   string
+	Status         string
+}
+
+var UnknownClientState = errors.New("unknown client state type")
+
+func LightClientStateFromProtoAny(any *types.Any) (*LightClientState, error) {
+	switch any.TypeUrl {
+	case "/ibc.lightclients.tendermint.v1.ClientState":
+		cs := &ibctm.ClientState{}
+		if err := cs.Unmarshal(any.Value); err != nil {
+			return nil, err
+		}
+		return &LightClientState{
+			ClientType:     cs.ClientType(),
+			ChainId:        cs.ChainId,
+			LatestHeight:   cs.LatestHeight.String(),
+			TrustingPeriod: cs.TrustingPeriod,
+			FrozenHeight:   cs.FrozenHeight.String(),
+		}, nil
+	default:
+		return nil, UnknownClientState
 	}
 }
 
@@ -66,6 +165,77 @@ func (v *AnyVestingAccount) String() string {
 	}
 }
 
+// VestingAccount returns the account as the SDK vesting account, evaluating its schedule with the SDK logic
+func (v *AnyVestingAccount) VestingAccount() vestexported.VestingAccount {
+	switch v.AccountType {
+	case Delayed:
+		return v.Delayed
+	case Continuous:
+		return v.Continuous
+	case Periodic:
+		return v.Periodic
+	case PermanentLocked:
+		return v.PermanentLocked
+	default:
+		return nil
+	}
+}
+
+// Base returns the vesting fields shared by all vesting account types
+func (v *AnyVestingAccount) Base() *vestingtypes.BaseVestingAccount {
+	switch v.AccountType {
+	case Delayed:
+		return v.Delayed.BaseVestingAccount
+	case Continuous:
+		return v.Continuous.BaseVestingAccount
+	case Periodic:
+		return v.Periodic.BaseVestingAccount
+	case PermanentLocked:
+		return v.PermanentLocked.BaseVestingAccount
+	default:
+		return nil
+	}
+}
+
+// VestingAccountWithBalances is a vesting account with what it holds: its bank balances, the balance of its
+// delegations and the remaining balance of its unbonding delegations
+type VestingAccountWithBalances struct {
+	Account   *AnyVestingAccount
+	Balances  sdk.Coins
+	Spendable sdk.Coins
+	Delegated sdk.Coins
+	Unbonding sdk.Coins
+}
+
+// VestingSchedule is a vesting account evaluated at a point in time
+type VestingSchedule struct {
+	Account *AnyVestingAccount
+	At      time.Time
+	Vested  sdk.Coins
+	Vesting sdk.Coins
+	// NextUnlockTime is nil when no discrete unlock is left, e.g. for continuous and permanently locked accounts
+	NextUnlockTime   *time.Time
+	NextUnlockAmount sdk.Coins
+	// Periods is the cumulative schedule of periodic accounts
+	Periods []VestingSchedulePeriod
+}
+
+type VestingSchedulePeriod struct {
+	EndTime    time.Time
+	Amount     sdk.Coins
+	Cumulative sdk.Coins
+	Vested     bool
+}
+
+// VestingCalendarEntry is the amount of a denom unlocking in the period starting at PeriodStart,
+// Cumulative is the total vested amount at the end of the period, including what vested before the calendar starts
+type VestingCalendarEntry struct {
+	PeriodStart time.Time
+	Denom       string
+	Amount      sdk.Int
+	Cumulative  sdk.Int
+}
+
 var NotVestingAccount = errors.New("not a vesting account")
 
 func AnyVestingAccountFromProtoAny(any *types.Any) (*AnyVestingAccount, error) {
@@ -101,3 +271,142 @@ func AnyVestingAccountFromProtoAny(any *types.Any) (*AnyVestingAccount, error) {
 	}
 	return account, nil
 }
+
+// Account holds the fields shared by all account kinds, Type is the proto message name, e.g. ModuleAccount,
+// or the type URL of account kinds this app does not know
+type Account struct {
+	Type          string
+	Address       string
+	AccountNumber uint64
+	Sequence      uint64
+	// PubKeyType is the type URL of the public key, empty until the account signed a transaction
+	PubKeyType string
+	// ModuleName and Permissions are set for module accounts
+	ModuleName  string
+	Permissions []string
+	// Owner is the controller chain owner of interchain accounts
+	Owner string
+}
+
+func AccountFromProtoAny(any *types.Any) (*Account, error) {
+	var base *authtypes.BaseAccount
+	account := &Account{Type: any.TypeUrl[strings.LastIndex(any.TypeUrl, ".")+1:]}
+
+	switch any.TypeUrl {
+	case "/cosmos.auth.v1beta1.BaseAccount":
+		base = &authtypes.BaseAccount{}
+		if err := base.Unmarshal(any.Value); err != nil {
+			return nil, err
+		}
+	case "/cosmos.auth.v1beta1.ModuleAccount":
+		acc := &authtypes.ModuleAccount{}
+		if err := acc.Unmarshal(any.Value); err != nil {
+			return nil, err
+		}
+		base = acc.BaseAccount
+		account.ModuleName = acc.Name
+		account.Permissions = acc.Permissions
+	case "/ibc.applications.interchain_accounts.v1.InterchainAccount":
+		acc := &icatypes.InterchainAccount{}
+		if err := acc.Unmarshal(any.Value); err != nil {
+			return nil, err
+		}
+		base = acc.BaseAccount
+		account.Owner = acc.AccountOwner
+	default:
+		vestingAccount, err := AnyVestingAccountFromProtoAny(any)
+		if errors.Is(err, NotVestingAccount) {
+			account.Type = any.TypeUrl
+			base = unknownBaseAccount(any.Value)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		base = vestingAccount.Base().BaseAccount
+	}
+
+	if base != nil {
+		account.Address = base.Address
+		account.AccountNumber = base.AccountNumber
+		account.Sequence = base.Sequence
+		if base.PubKey != nil {
+			account.PubKeyType = base.PubKey.TypeUrl
+		}
+	}
+	return account, nil
+}
+
+// unknownBaseAccount finds the BaseAccount of an account type the codec does not know. Accounts are one or embed it as
+// their first field, directly or within a base of their own as vesting accounts do, so the message and its first fields
+// are tried in turn until one decodes into a BaseAccount with a bech32 address. It returns nil when none does.
+func unknownBaseAccount(value []byte) *authtypes.BaseAccount {
+	for depth := 0; depth < 4; depth++ {
+		base := &authtypes.BaseAccount{}
+		if err := base.Unmarshal(value); err == nil {
+			if _, _, err := bech32.DecodeAndConvert(base.Address); err == nil {
+				return base
+			}
+		}
+		field, ok := firstField(value)
+		if !ok {
+			return nil
+		}
+		value = field
+	}
+	return nil
+}
+
+// firstField returns the bytes of the field 1 of a protobuf message when it is a length delimited one
+func firstField(b []byte) ([]byte, bool) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, false
+		}
+		b = b[n:]
+		if num == 1 && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			return v, n >= 0
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, false
+		}
+		b = b[n:]
+	}
+	return nil, false
+}
+
+// Holder is an address holding a denom, Total is the sum of its liquid, staked and unbonding amounts
+type Holder struct {
+	Rank      int
+	Address   string
+	Liquid    sdk.Int
+	Staked    sdk.Int
+	Unbonding sdk.Int
+	Total     sdk.Int
+	// Share is the part of the supply held by the address, CumulativeShare the part held by the addresses up to its rank
+	Share           sdk.Dec
+	CumulativeShare sdk.Dec
+}
+
+// HoldersReport is the distribution of a denom, holders are ranked by decreasing total
+type HoldersReport struct {
+	Denom   string
+	Supply  sdk.Int
+	Holders []Holder
+	// Gini is the Gini coefficient of the holder totals, 0 when all hold the same amount and close to 1 when one holds everything
+	Gini sdk.Dec
+}
+
+// TopShare returns the part of the supply held by the n largest holders
+func (r HoldersReport) TopShare(n int) sdk.Dec {
+	if n > len(r.Holders) {
+		n = len(r.Holders)
+	}
+	if n <= 0 {
+		return sdk.ZeroDec()
+	}
+	return r.Holders[n-1].CumulativeShare
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"QuicksilverDumper/workerpool"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// StreamAllUnbondingEntries calls fn with the unbonding delegation entries of every validator, in validator order.
+// Unbonding delegations of up to uc.Concurrency validators are fetched in parallel.
+func (uc *UseCase) StreamAllUnbondingEntries(ctx context.Context, fn func([]UnbondingEntry) error) error {
+	bondDenom, validators, err := uc.getBondDenomAndValidators(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = workerpool.MapOrdered(ctx, uc.Concurrency, validators,
+		func(ctx context.Context, validator stakingtypes.Validator) ([]UnbondingEntry, error) {
+			uc.Logger.Infof(fmt.Sprintf("Getting unbonding delegations for validator: %s", validator.OperatorAddress))
+			var entries []UnbondingEntry
+			err := uc.Cli.StreamValidatorUnbondingDelegations(ctx, validator.OperatorAddress, func(unbondings []stakingtypes.UnbondingDelegation) error {
+				for _, ubd := range unbondings {
+					created := map[int64]int{}
+					for _, entry := range ubd.Entries {
+						entries = append(entries, UnbondingEntry{
+							DelegatorAddress: ubd.DelegatorAddress,
+							ValidatorAddress: ubd.ValidatorAddress,
+							Entry:            entryIndex(created, entry.CreationHeight),
+							CreationHeight:   entry.CreationHeight,
+							CompletionTime:   entry.CompletionTime,
+							InitialBalance:   sdk.Coin{Denom: bondDenom, Amount: entry.InitialBalance},
+							Balance:          sdk.Coin{Denom: bondDenom, Amount: entry.Balance},
+						})
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get unbonding delegations for validator %s: %w", validator.OperatorAddress, err)
+			}
+			return entries, nil
+		},
+		func(entries []UnbondingEntry) error {
+			count += len(entries)
+			return fn(entries)
+		},
+	)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get validator unbonding delegations: %e", err.Error())
+		return err
+	}
+
+	uc.Logger.Infof(fmt.Sprintf("Found %d unbonding entries", count))
+	return nil
+}
+
+// StreamAllRedelegationEntries calls fn with the redelegation entries from every source validator, in validator order.
+// Redelegations of up to uc.Concurrency validators are fetched in parallel.
+func (uc *UseCase) StreamAllRedelegationEntries(ctx context.Context, fn func([]RedelegationEntry) error) error {
+	bondDenom, validators, err := uc.getBondDenomAndValidators(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = workerpool.MapOrdered(ctx, uc.Concurrency, validators,
+		func(ctx context.Context, validator stakingtypes.Validator) ([]RedelegationEntry, error) {
+			uc.Logger.Infof(fmt.Sprintf("Getting redelegations from validator: %s", validator.OperatorAddress))
+			var entries []RedelegationEntry
+			err := uc.Cli.StreamValidatorRedelegations(ctx, validator.OperatorAddress, func(redelegations stakingtypes.RedelegationResponses) error {
+				for _, red := range redelegations {
+					created := map[int64]int{}
+					for _, entry := range red.Entries {
+						entries = append(entries, RedelegationEntry{
+							DelegatorAddress:    red.Redelegation.DelegatorAddress,
+							SrcValidatorAddress: red.Redelegation.ValidatorSrcAddress,
+							DstValidatorAddress: red.Redelegation.ValidatorDstAddress,
+							Entry:               entryIndex(created, entry.RedelegationEntry.CreationHeight),
+							CreationHeight:      entry.RedelegationEntry.CreationHeight,
+							CompletionTime:      entry.RedelegationEntry.CompletionTime,
+							InitialBalance:      sdk.Coin{Denom: bondDenom, Amount: entry.RedelegationEntry.InitialBalance},
+							Balance:             sdk.Coin{Denom: bondDenom, Amount: entry.Balance},
+							SharesDst:           entry.RedelegationEntry.SharesDst,
+						})
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get redelegations from validator %s: %w", validator.OperatorAddress, err)
+			}
+			return entries, nil
+		},
+		func(entries []RedelegationEntry) error {
+			count += len(entries)
+			return fn(entries)
+		},
+	)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get validator redelegations: %e", err.Error())
+		return err
+	}
+
+	uc.Logger.Infof(fmt.Sprintf("Found %d redelegation entries", count))
+	return nil
+}
+
+// entryIndex numbers the entries created at the same height. Unlike the position in the entries, which shifts as earlier
+// entries mature, it is stable as entries created at the same height mature together.
+func entryIndex(created map[int64]int, creationHeight int64) int {
+	index := created[creationHeight]
+	created[creationHeight]++
+	return index
+}
+
+// getBondDenomAndValidators gets the bond denom, unbonding and redelegation entries hold bare amounts of it, and all validators
+func (uc *UseCase) getBondDenomAndValidators(ctx context.Context) (string, []stakingtypes.Validator, error) {
+	bondDenom, err := uc.Cli.GetBondDenom(ctx)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get bond denom: %e", err.Error())
+		return "", nil, fmt.Errorf("failed to get bond denom: %w", err)
+	}
+
+	uc.Logger.Infof("Getting all validators")
+	validators, err := uc.Cli.GetAllValidators(ctx)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get all validators: %e", err.Error())
+		return "", nil, fmt.Errorf("failed to get all validators: %w", err)
+	}
+	return bondDenom, validators, nil
+}
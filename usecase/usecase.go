@@ -3,19 +3,41 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibcConnection "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
 	ibcCore "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
 	icstypes "github.com/ingenuity-build/quicksilver/x/interchainstaking/types"
 )
 
 type Client interface {
 	GetAllAccounts(ctx context.Context) ([]*types.Any, error)
+	StreamAllAccounts(ctx context.Context, fn func([]*types.Any) error) error
 	GetAllIBCChannels(ctx context.Context) ([]*ibcCore.IdentifiedChannel, error)
 	GetAllICSReceipts(ctx context.Context) ([]icstypes.Receipt, error)
 	GetValidatorDelegations(ctx context.Context, validatorAddr string) (stakingtypes.DelegationResponses, error)
+	StreamValidatorDelegations(ctx context.Context, validatorAddr string, fn func(stakingtypes.DelegationResponses) error) error
 	GetAllValidators(ctx context.Context) ([]stakingtypes.Validator, error)
+	GetIBCConnection(ctx context.Context, connectionId string) (*ibcConnection.ConnectionEnd, error)
+	GetIBCClientState(ctx context.Context, clientId string) (*types.Any, error)
+	GetIBCClientStatus(ctx context.Context, clientId string) (string, error)
+	GetAllDenomsMetadata(ctx context.Context) ([]banktypes.Metadata, error)
+	GetAllBalances(ctx context.Context, address string) (sdk.Coins, error)
+	GetSpendableBalances(ctx context.Context, address string) (sdk.Coins, error)
+	GetDelegatorDelegations(ctx context.Context, delegatorAddr string) (stakingtypes.DelegationResponses, error)
+	GetDelegatorUnbondingDelegations(ctx context.Context, delegatorAddr string) ([]stakingtypes.UnbondingDelegation, error)
+	GetBondDenom(ctx context.Context) (string, error)
+	GetValidatorUnbondingDelegations(ctx context.Context, validatorAddr string) ([]stakingtypes.UnbondingDelegation, error)
+	StreamValidatorUnbondingDelegations(ctx context.Context, validatorAddr string, fn func([]stakingtypes.UnbondingDelegation) error) error
+	GetValidatorRedelegations(ctx context.Context, srcValidatorAddr string) (stakingtypes.RedelegationResponses, error)
+	StreamValidatorRedelegations(ctx context.Context, srcValidatorAddr string, fn func(stakingtypes.RedelegationResponses) error) error
+	StreamDenomOwners(ctx context.Context, denom string, fn func([]*banktypes.DenomOwner) error) error
+	GetSupplyOf(ctx context.Context, denom string) (sdk.Coin, error)
+	GetModuleAccountAddress(ctx context.Context, name string) (string, error)
 }
 
 type Logger interface {
@@ -26,11 +48,15 @@ type Logger interface {
 type UseCase struct {
 	Cli    Client
 	Logger Logger
+	// Concurrency bounds the number of parallel per-entity queries, e.g. per-validator delegations
+	Concurrency int
 }
 
 func NewUseCase(client Client, logger Logger) *UseCase {
 	return &UseCase{
-		client, logger,
+		Cli:         client,
+		Logger:      logger,
+		Concurrency: 1,
 	}
 }
 
@@ -54,8 +80,26 @@ func (uc *UseCase) GetPendingStakingReceipts(ctx context.Context) ([]icstypes.Re
 	return pendingReceipts, nil
 }
 
-// GetChannelsStatuses gets the statuses of all IBC channels
-func (uc *UseCase) GetChannelsStatuses(ctx context.Context) ([]*ChannelStatus, error) {
+// GetChannelsStatuses gets the statuses of all IBC channels matching the given filter
+func (uc *UseCase) GetChannelsStatuses(ctx context.Context, filter ChannelsFilter) ([]*ChannelStatus, error) {
+	if filter.CounterpartyChainId != "" {
+		// counterparty chain is only known after resolving the channel's light client
+		clientStates, err := uc.GetChannelsClientStates(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		channelsStatuses := make([]*ChannelStatus, 0, len(clientStates))
+		for _, cs := range clientStates {
+			channelsStatuses = append(channelsStatuses, &cs.ChannelStatus)
+		}
+		return channelsStatuses, nil
+	}
+
+	return uc.getChannelsStatuses(ctx, filter)
+}
+
+// getChannelsStatuses gets the statuses of all IBC channels, filtered by port prefix only
+func (uc *UseCase) getChannelsStatuses(ctx context.Context, filter ChannelsFilter) ([]*ChannelStatus, error) {
 	uc.Logger.Infof("Getting all IBC channels")
 	channels, err := uc.Cli.GetAllIBCChannels(ctx)
 	if err != nil {
@@ -66,10 +110,25 @@ func (uc *UseCase) GetChannelsStatuses(ctx context.Context) ([]*ChannelStatus, e
 	uc.Logger.Infof("Parsing channel statuses")
 	channelsStatuses := parseChannels(channels)
 
+	if filter.PortPrefix != "" {
+		channelsStatuses = filterChannelsByPort(channelsStatuses, filter.PortPrefix)
+	}
+
 	uc.Logger.Infof(fmt.Sprintf("Found %d channel statuses", len(channelsStatuses)))
 	return channelsStatuses, nil
 }
 
+// filterChannelsByPort keeps the channels whose source port starts with the given prefix
+func filterChannelsByPort(channels []*ChannelStatus, prefix string) []*ChannelStatus {
+	var filtered []*ChannelStatus
+	for _, ch := range channels {
+		if strings.HasPrefix(ch.SourcePortId, prefix) {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
+}
+
 // parseChannels parses the statuses of the given channels
 func parseChannels(channels []*ibcCore.IdentifiedChannel) []*ChannelStatus {
 	var statuses []*ChannelStatus
@@ -81,22 +140,40 @@ func parseChannels(channels []*ibcCore.IdentifiedChannel) []*ChannelStatus {
 
 // GetAllVestingAccounts gets all vesting accounts
 func (uc *UseCase) GetAllVestingAccounts(ctx context.Context) ([]*AnyVestingAccount, error) {
-	uc.Logger.Infof("Getting all accounts")
-	accounts, err := uc.Cli.GetAllAccounts(ctx)
+	var vestingAccounts []*AnyVestingAccount
+	err := uc.StreamAllVestingAccounts(ctx, func(accounts []*AnyVestingAccount) error {
+		vestingAccounts = append(vestingAccounts, accounts...)
+		return nil
+	})
 	if err != nil {
-		uc.Logger.Errorf("Failed to get all accounts: %e", err.Error())
 		return nil, err
 	}
+	return vestingAccounts, nil
+}
 
-	uc.Logger.Infof("Extracting vesting accounts")
-	vestingAccounts, err := extractVestingAccounts(accounts)
+// StreamAllVestingAccounts calls fn with the vesting accounts of every accounts page as soon as the page arrives
+func (uc *UseCase) StreamAllVestingAccounts(ctx context.Context, fn func([]*AnyVestingAccount) error) error {
+	uc.Logger.Infof("Streaming all accounts")
+	count := 0
+	err := uc.Cli.StreamAllAccounts(ctx, func(accounts []*types.Any) error {
+		vestingAccounts, err := extractVestingAccounts(accounts)
+		if err != nil {
+			uc.Logger.Errorf("Failed to extract vesting accounts: %e", err.Error())
+			return err
+		}
+		if len(vestingAccounts) == 0 {
+			return nil
+		}
+		count += len(vestingAccounts)
+		return fn(vestingAccounts)
+	})
 	if err != nil {
-		uc.Logger.Errorf("Failed to extract vesting accounts: %e", err.Error())
-		return nil, err
+		uc.Logger.Errorf("Failed to stream all accounts: %e", err.Error())
+		return err
 	}
 
-	uc.Logger.Infof(fmt.Sprintf("Found %d vesting accounts", len(vestingAccounts)))
-	return vestingAccounts, nil
+	uc.Logger.Infof(fmt.Sprintf("Found %d vesting accounts", count))
+	return nil
 }
 
 // extractVestingAccounts extracts vesting accounts from the given accounts
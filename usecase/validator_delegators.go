@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"sort"
 
+	"QuicksilverDumper/workerpool"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
@@ -15,27 +17,53 @@ type sharesAndCoins struct {
 
 // GetAllValidatorsAndDelegators gets all validators and their delegators
 func (uc *UseCase) GetAllValidatorsAndDelegators(ctx context.Context) (validators []ValidatorWithDelegators, err error) {
+	err = uc.StreamAllValidatorsAndDelegators(ctx, func(page []ValidatorWithDelegators) error {
+		validators = append(validators, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validators, nil
+}
+
+// StreamAllValidatorsAndDelegators calls fn with the delegators of every validator, in validator order, as soon as they are fetched.
+// Delegations of up to uc.Concurrency validators are fetched in parallel.
+func (uc *UseCase) StreamAllValidatorsAndDelegators(ctx context.Context, fn func([]ValidatorWithDelegators) error) error {
 	uc.Logger.Infof("Getting all validators")
 	allValidators, err := uc.Cli.GetAllValidators(ctx)
 	if err != nil {
 		uc.Logger.Errorf("Failed to get all validators: %e", err.Error())
-		return nil, err
+		return err
 	}
 
-	for _, validator := range allValidators {
-		uc.Logger.Infof(fmt.Sprintf("Getting delegations for validator: %s", validator.OperatorAddress))
-		delegations, err := uc.Cli.GetValidatorDelegations(ctx, validator.OperatorAddress)
-		if err != nil {
-			uc.Logger.Errorf("Failed to get validator delegations: %e", err.Error())
-			return nil, err
-		}
-
-		uc.Logger.Infof("Getting validator delegators")
-		validators = append(validators, uc.getValidatorDelegators(delegations)...)
+	count := 0
+	err = workerpool.MapOrdered(ctx, uc.Concurrency, allValidators,
+		func(ctx context.Context, validator stakingtypes.Validator) ([]ValidatorWithDelegators, error) {
+			uc.Logger.Infof(fmt.Sprintf("Getting delegations for validator: %s", validator.OperatorAddress))
+			var delegators []ValidatorWithDelegators
+			err := uc.Cli.StreamValidatorDelegations(ctx, validator.OperatorAddress, func(delegations stakingtypes.DelegationResponses) error {
+				// a delegator has at most one delegation per validator, so pages never split a delegator
+				delegators = append(delegators, uc.getValidatorDelegators(delegations)...)
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get delegations for validator %s: %w", validator.OperatorAddress, err)
+			}
+			return delegators, nil
+		},
+		func(delegators []ValidatorWithDelegators) error {
+			count += len(delegators)
+			return fn(delegators)
+		},
+	)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get validator delegations: %e", err.Error())
+		return err
 	}
 
-	uc.Logger.Infof(fmt.Sprintf("Found %d validators with delegators", len(validators)))
-	return validators, nil
+	uc.Logger.Infof(fmt.Sprintf("Found %d validators with delegators", count))
+	return nil
 }
 
 // getValidatorDelegators gets the delegators for a validator
@@ -54,9 +82,13 @@ func (uc *UseCase) getValidatorDelegators(delegations stakingtypes.DelegationRes
 		for denom, amount := range dc.Coins {
 			vw.Delegations = append(vw.Delegations, sdk.NewCoin(denom, amount))
 		}
+		vw.Delegations = vw.Delegations.Sort()
 
 		validators = append(validators, vw)
 	}
+	sort.Slice(validators, func(i, j int) bool {
+		return validators[i].DelegatorAddress < validators[j].DelegatorAddress
+	})
 	return
 }
 
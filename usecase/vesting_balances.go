@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"QuicksilverDumper/workerpool"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// StreamAllVestingAccountsWithBalances calls fn with the vesting accounts of every accounts page joined with their
+// balances, delegations and unbonding delegations. Up to uc.Concurrency accounts are queried in parallel.
+func (uc *UseCase) StreamAllVestingAccountsWithBalances(ctx context.Context, fn func([]VestingAccountWithBalances) error) error {
+	bondDenom, err := uc.Cli.GetBondDenom(ctx)
+	if err != nil {
+		uc.Logger.Errorf("Failed to get bond denom: %e", err.Error())
+		return fmt.Errorf("failed to get bond denom: %w", err)
+	}
+
+	return uc.StreamAllVestingAccounts(ctx, func(accounts []*AnyVestingAccount) error {
+		joined := make([]VestingAccountWithBalances, 0, len(accounts))
+		err := workerpool.MapOrdered(ctx, uc.Concurrency, accounts,
+			func(ctx context.Context, account *AnyVestingAccount) (VestingAccountWithBalances, error) {
+				return uc.getVestingAccountBalances(ctx, account, bondDenom)
+			},
+			func(account VestingAccountWithBalances) error {
+				joined = append(joined, account)
+				return nil
+			},
+		)
+		if err != nil {
+			uc.Logger.Errorf("Failed to get vesting accounts balances: %e", err.Error())
+			return err
+		}
+		return fn(joined)
+	})
+}
+
+func (uc *UseCase) getVestingAccountBalances(ctx context.Context, account *AnyVestingAccount, bondDenom string) (VestingAccountWithBalances, error) {
+	address := account.Base().Address
+	result := VestingAccountWithBalances{Account: account}
+
+	var err error
+	if result.Balances, err = uc.Cli.GetAllBalances(ctx, address); err != nil {
+		return result, fmt.Errorf("failed to get balances of %s: %w", address, err)
+	}
+	if result.Spendable, err = uc.Cli.GetSpendableBalances(ctx, address); err != nil {
+		return result, fmt.Errorf("failed to get spendable balances of %s: %w", address, err)
+	}
+
+	delegations, err := uc.Cli.GetDelegatorDelegations(ctx, address)
+	if err != nil {
+		return result, fmt.Errorf("failed to get delegations of %s: %w", address, err)
+	}
+	result.Delegated = sdk.Coins{}
+	for _, delegation := range delegations {
+		result.Delegated = result.Delegated.Add(delegation.Balance)
+	}
+
+	unbondings, err := uc.Cli.GetDelegatorUnbondingDelegations(ctx, address)
+	if err != nil {
+		return result, fmt.Errorf("failed to get unbonding delegations of %s: %w", address, err)
+	}
+	// unbonding entries hold bare amounts of the bond denom
+	unbonding := sdk.ZeroInt()
+	for _, ubd := range unbondings {
+		for _, entry := range ubd.Entries {
+			unbonding = unbonding.Add(entry.Balance)
+		}
+	}
+	result.Unbonding = sdk.Coins{}
+	if unbonding.IsPositive() {
+		result.Unbonding = sdk.Coins{sdk.Coin{Denom: bondDenom, Amount: unbonding}}
+	}
+	return result, nil
+}
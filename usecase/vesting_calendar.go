@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type CalendarInterval string
+
+const (
+	Day   CalendarInterval = "day"
+	Week  CalendarInterval = "week"
+	Month CalendarInterval = "month"
+)
+
+// ParseCalendarInterval parses day, week or month
+func ParseCalendarInterval(value string) (CalendarInterval, error) {
+	switch interval := CalendarInterval(value); interval {
+	case Day, Week, Month:
+		return interval, nil
+	default:
+		return "", fmt.Errorf("unknown interval %q, expected day, week or month", value)
+	}
+}
+
+// start truncates t to the start of its interval in UTC, weeks start on Monday
+func (i CalendarInterval) start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch i {
+	case Week:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// next returns the start of the interval following the one starting at start
+func (i CalendarInterval) next(start time.Time) time.Time {
+	switch i {
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// GetVestingCalendar buckets the unlocks of all vesting accounts after from by interval and denom, up to the last
+// vesting end time. Unlocks are the growth of the vested coins computed with the SDK vesting logic, so continuous
+// accounts are spread over their buckets. The first bucket only holds the unlocks after from.
+// Every account is only evaluated at its own unlock times, so a far-off end time does not sample every bucket up to it.
+func (uc *UseCase) GetVestingCalendar(ctx context.Context, from time.Time, interval CalendarInterval) ([]VestingCalendarEntry, error) {
+	accounts, err := uc.GetAllVestingAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all vesting accounts: %w", err)
+	}
+
+	// vested coins at from, then the unlocks of every bucket keyed by its start
+	vestedAtFrom := sdk.NewCoins()
+	unlocks := make(map[time.Time]sdk.Coins)
+	for _, account := range accounts {
+		vestingAccount := account.VestingAccount()
+		previous := vestingAccount.GetVestedCoins(from)
+		vestedAtFrom = vestedAtFrom.Add(previous...)
+		for _, cut := range unlockCuts(account, from, interval) {
+			vested := vestingAccount.GetVestedCoins(cut)
+			if unlocked := vested.Sub(previous...); !unlocked.IsZero() {
+				start := interval.start(cut)
+				unlocks[start] = unlocks[start].Add(unlocked...)
+			}
+			previous = vested
+		}
+	}
+
+	starts := make([]time.Time, 0, len(unlocks))
+	for start := range unlocks {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	var calendar []VestingCalendarEntry
+	cumulative := vestedAtFrom
+	for _, start := range starts {
+		cumulative = cumulative.Add(unlocks[start]...)
+		for _, coin := range unlocks[start] {
+			calendar = append(calendar, VestingCalendarEntry{
+				PeriodStart: start,
+				Denom:       coin.Denom,
+				Amount:      coin.Amount,
+				Cumulative:  cumulative.AmountOf(coin.Denom),
+			})
+		}
+	}
+
+	until := from
+	if len(starts) > 0 {
+		until = starts[len(starts)-1]
+	}
+	uc.Logger.Infof(fmt.Sprintf("Found %d unlocks of %d vesting accounts until %s", len(calendar), len(accounts), until.UTC().Format(time.RFC3339)))
+	return calendar, nil
+}
+
+// unlockCuts returns the times after from at which the vested coins of an account can grow: the unlock times of delayed
+// and periodic accounts, and the end of every bucket of the vesting range of continuous accounts, which unlock every block
+func unlockCuts(account *AnyVestingAccount, from time.Time, interval CalendarInterval) []time.Time {
+	var cuts []time.Time
+	switch account.AccountType {
+	case Delayed:
+		cuts = append(cuts, time.Unix(account.Delayed.EndTime, 0))
+	case Periodic:
+		endTime := account.Periodic.StartTime
+		for _, period := range account.Periodic.VestingPeriods {
+			endTime += period.Length
+			cuts = append(cuts, time.Unix(endTime, 0))
+		}
+	case Continuous:
+		startTime := time.Unix(account.Continuous.StartTime, 0)
+		if startTime.Before(from) {
+			startTime = from
+		}
+		endTime := time.Unix(account.Continuous.EndTime, 0)
+		for start := interval.start(startTime); !start.After(endTime); start = interval.next(start) {
+			// the SDK vests at block times reaching the unlock time, a second before the next bucket keeps it in this one
+			cuts = append(cuts, interval.next(start).Add(-time.Second))
+		}
+	}
+
+	after := cuts[:0]
+	for _, cut := range cuts {
+		if cut.After(from) {
+			after = append(after, cut)
+		}
+	}
+	return after
+}
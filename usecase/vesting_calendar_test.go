@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+)
+
+// fakeClient serves a fixed set of accounts, the other queries are not implemented
+type fakeClient struct {
+	Client
+	accounts []*types.Any
+}
+
+func (c *fakeClient) StreamAllAccounts(_ context.Context, fn func([]*types.Any) error) error {
+	return fn(c.accounts)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+func newFakeUseCase(t *testing.T, accounts ...vestexported.VestingAccount) *UseCase {
+	t.Helper()
+	client := &fakeClient{}
+	for _, account := range accounts {
+		any, err := types.NewAnyWithValue(account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.accounts = append(client.accounts, any)
+	}
+	return NewUseCase(client, nopLogger{})
+}
+
+func baseAccount(name string) *authtypes.BaseAccount {
+	return authtypes.NewBaseAccountWithAddress(sdk.AccAddress(name))
+}
+
+func date(year int, month time.Month, day, hour, min, sec int) time.Time {
+	return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+}
+
+func entry(start time.Time, denom string, amount, cumulative int64) VestingCalendarEntry {
+	return VestingCalendarEntry{PeriodStart: start, Denom: denom, Amount: sdk.NewInt(amount), Cumulative: sdk.NewInt(cumulative)}
+}
+
+func TestGetVestingCalendar(t *testing.T) {
+	// a Wednesday
+	from := date(2024, time.January, 10, 12, 0, 0)
+	uqck := func(amount int64) sdk.Coins { return sdk.NewCoins(sdk.NewInt64Coin("uqck", amount)) }
+
+	tests := []struct {
+		name     string
+		interval CalendarInterval
+		accounts []vestexported.VestingAccount
+		want     []VestingCalendarEntry
+	}{
+		{
+			name:     "unlock at the start of a bucket belongs to it",
+			interval: Month,
+			accounts: []vestexported.VestingAccount{
+				vestingtypes.NewDelayedVestingAccount(baseAccount("a"), uqck(100), date(2024, time.February, 1, 0, 0, 0).Unix()),
+				vestingtypes.NewDelayedVestingAccount(baseAccount("b"), uqck(10), date(2024, time.January, 31, 23, 59, 59).Unix()),
+			},
+			want: []VestingCalendarEntry{
+				entry(date(2024, time.January, 1, 0, 0, 0), "uqck", 10, 10),
+				entry(date(2024, time.February, 1, 0, 0, 0), "uqck", 100, 110),
+			},
+		},
+		{
+			name:     "weeks start on Monday",
+			interval: Week,
+			accounts: []vestexported.VestingAccount{
+				// a Sunday, then the following Monday
+				vestingtypes.NewDelayedVestingAccount(baseAccount("a"), uqck(100), date(2024, time.January, 14, 10, 0, 0).Unix()),
+				vestingtypes.NewDelayedVestingAccount(baseAccount("b"), uqck(10), date(2024, time.January, 15, 0, 0, 0).Unix()),
+			},
+			want: []VestingCalendarEntry{
+				entry(date(2024, time.January, 8, 0, 0, 0), "uqck", 100, 100),
+				entry(date(2024, time.January, 15, 0, 0, 0), "uqck", 10, 110),
+			},
+		},
+		{
+			name:     "first bucket only holds the unlocks after from",
+			interval: Day,
+			accounts: []vestexported.VestingAccount{
+				// 200uqck over two days, 50 of them vested by from
+				vestingtypes.NewContinuousVestingAccount(baseAccount("a"), uqck(200),
+					date(2024, time.January, 10, 0, 0, 0).Unix(), date(2024, time.January, 12, 0, 0, 0).Unix()),
+			},
+			want: []VestingCalendarEntry{
+				// vested at 23:59:59 is 200*86399/172800 rounded
+				entry(date(2024, time.January, 10, 0, 0, 0), "uqck", 50, 100),
+				entry(date(2024, time.January, 11, 0, 0, 0), "uqck", 100, 200),
+			},
+		},
+		{
+			name:     "periods vested before from are only cumulated",
+			interval: Month,
+			accounts: []vestexported.VestingAccount{
+				vestingtypes.NewPeriodicVestingAccount(baseAccount("a"),
+					sdk.NewCoins(sdk.NewInt64Coin("uqck", 60), sdk.NewInt64Coin("uatom", 5)),
+					date(2024, time.January, 1, 0, 0, 0).Unix(),
+					vestingtypes.Periods{
+						{Length: 3 * 24 * 3600, Amount: uqck(10)},
+						{Length: 28 * 24 * 3600, Amount: uqck(20)},
+						{Length: 29 * 24 * 3600, Amount: sdk.NewCoins(sdk.NewInt64Coin("uqck", 30), sdk.NewInt64Coin("uatom", 5))},
+					}),
+			},
+			want: []VestingCalendarEntry{
+				entry(date(2024, time.February, 1, 0, 0, 0), "uqck", 20, 30),
+				entry(date(2024, time.March, 1, 0, 0, 0), "uatom", 5, 5),
+				entry(date(2024, time.March, 1, 0, 0, 0), "uqck", 30, 60),
+			},
+		},
+		{
+			name:     "far-off end time is a single unlock",
+			interval: Day,
+			accounts: []vestexported.VestingAccount{
+				vestingtypes.NewDelayedVestingAccount(baseAccount("a"), uqck(100), date(9999, time.December, 31, 0, 0, 0).Unix()),
+				vestingtypes.NewPeriodicVestingAccount(baseAccount("b"), uqck(10), from.Unix(),
+					vestingtypes.Periods{{Length: date(9999, time.December, 31, 0, 0, 0).Unix() - from.Unix(), Amount: uqck(10)}}),
+			},
+			want: []VestingCalendarEntry{
+				entry(date(9999, time.December, 31, 0, 0, 0), "uqck", 110, 110),
+			},
+		},
+		{
+			name:     "permanently locked accounts never unlock",
+			interval: Day,
+			accounts: []vestexported.VestingAccount{
+				vestingtypes.NewPermanentLockedAccount(baseAccount("a"), uqck(100)),
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newFakeUseCase(t, tt.accounts...)
+			got, err := uc.GetVestingCalendar(context.Background(), from, tt.interval)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got calendar %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GetAllVestingSchedules gets all vesting accounts evaluated at the given time
+func (uc *UseCase) GetAllVestingSchedules(ctx context.Context, at time.Time) ([]VestingSchedule, error) {
+	var schedules []VestingSchedule
+	err := uc.StreamAllVestingSchedules(ctx, at, func(page []VestingSchedule) error {
+		schedules = append(schedules, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// StreamAllVestingSchedules calls fn with the vesting accounts of every accounts page evaluated at the given time
+func (uc *UseCase) StreamAllVestingSchedules(ctx context.Context, at time.Time, fn func([]VestingSchedule) error) error {
+	uc.Logger.Infof(fmt.Sprintf("Evaluating vesting schedules at %s", at.UTC().Format(time.RFC3339)))
+	return uc.StreamAllVestingAccounts(ctx, func(accounts []*AnyVestingAccount) error {
+		schedules := make([]VestingSchedule, 0, len(accounts))
+		for _, account := range accounts {
+			schedules = append(schedules, EvaluateVestingAccount(account, at))
+		}
+		return fn(schedules)
+	})
+}
+
+// EvaluateVestingAccount computes the vested and vesting coins of the account at the given time with the SDK vesting
+// logic, and its next discrete unlock: the end time of delayed accounts and the next period end of periodic accounts
+func EvaluateVestingAccount(account *AnyVestingAccount, at time.Time) VestingSchedule {
+	vestingAccount := account.VestingAccount()
+	schedule := VestingSchedule{
+		Account: account,
+		At:      at,
+		Vested:  vestingAccount.GetVestedCoins(at),
+		Vesting: vestingAccount.GetVestingCoins(at),
+	}
+
+	switch account.AccountType {
+	case Delayed:
+		// the SDK vests delayed accounts once the block time reaches the end time
+		endTime := time.Unix(account.Delayed.EndTime, 0).UTC()
+		if at.Before(endTime) {
+			schedule.NextUnlockTime = &endTime
+			schedule.NextUnlockAmount = account.Delayed.OriginalVesting
+		}
+	case Periodic:
+		schedule.Periods = periodicSchedule(account, at)
+		for _, period := range schedule.Periods {
+			if !period.Vested {
+				endTime := period.EndTime
+				schedule.NextUnlockTime = &endTime
+				schedule.NextUnlockAmount = period.Amount
+				break
+			}
+		}
+	}
+	return schedule
+}
+
+// periodicSchedule lists the periods of a periodic account with their end time and the cumulative unlocked amount
+func periodicSchedule(account *AnyVestingAccount, at time.Time) []VestingSchedulePeriod {
+	periodic := account.Periodic
+	periods := make([]VestingSchedulePeriod, 0, len(periodic.VestingPeriods))
+	endTime := periodic.StartTime
+	cumulative := sdk.NewCoins()
+	for _, period := range periodic.VestingPeriods {
+		endTime += period.Length
+		cumulative = cumulative.Add(period.Amount...)
+		periods = append(periods, VestingSchedulePeriod{
+			EndTime:    time.Unix(endTime, 0).UTC(),
+			Amount:     period.Amount,
+			Cumulative: cumulative,
+			// same rule as the SDK: a period vests once the block time reaches its end
+			Vested: at.Unix() >= endTime,
+		})
+	}
+	return periods
+}
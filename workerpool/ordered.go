@@ -0,0 +1,85 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+type slot[Out any] struct {
+	out  Out
+	err  error
+	done chan struct{}
+}
+
+// MapOrdered runs fn for every input on at most concurrency goroutines and calls emit with the results in input order.
+// At most concurrency results are in flight or waiting to be emitted at any time, so memory stays bounded.
+// The first error, from fn or emit, cancels the context of the remaining calls and is returned.
+func MapOrdered[In, Out any](ctx context.Context, concurrency int, inputs []In, fn func(context.Context, In) (Out, error), emit func(Out) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	slots := make([]*slot[Out], len(inputs))
+	for i := range slots {
+		slots[i] = &slot[Out]{done: make(chan struct{})}
+	}
+
+	var firstErr error
+	var errOnce sync.Once
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	window := make(chan struct{}, concurrency)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, in := range inputs {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				for _, s := range slots[i:] {
+					s.err = ctx.Err()
+					close(s.done)
+				}
+				return
+			}
+
+			wg.Add(1)
+			go func(s *slot[Out], in In) {
+				defer wg.Done()
+				s.out, s.err = fn(ctx, in)
+				if s.err != nil {
+					fail(s.err)
+				}
+				close(s.done)
+			}(slots[i], in)
+		}
+	}()
+
+	for _, s := range slots {
+		<-s.done
+		if s.err != nil {
+			// a failing input cancels the others, report the error that caused it
+			fail(s.err)
+			return firstErr
+		}
+		if err := emit(s.out); err != nil {
+			fail(err)
+			return firstErr
+		}
+		var zero Out
+		s.out = zero
+		<-window
+	}
+	return nil
+}
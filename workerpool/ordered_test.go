@@ -0,0 +1,130 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func inputs(n int) []int {
+	in := make([]int, n)
+	for i := range in {
+		in[i] = i
+	}
+	return in
+}
+
+func TestMapOrderedKeepsInputOrder(t *testing.T) {
+	const concurrency = 8
+	var running, maxRunning int32
+
+	var got []int
+	err := MapOrdered(context.Background(), concurrency, inputs(200),
+		func(ctx context.Context, i int) (int, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+			return i * 2, nil
+		},
+		func(out int) error {
+			got = append(got, out)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 200 {
+		t.Fatalf("got %d results, want 200", len(got))
+	}
+	for i, out := range got {
+		if out != i*2 {
+			t.Fatalf("result %d is %d, want %d", i, out, i*2)
+		}
+	}
+	if maxRunning > concurrency {
+		t.Errorf("%d calls ran at once, want at most %d", maxRunning, concurrency)
+	}
+}
+
+func TestMapOrderedCancelsOnFirstError(t *testing.T) {
+	errFailed := errors.New("failed")
+	var cancelled int32
+
+	var got []int
+	err := MapOrdered(context.Background(), 4, inputs(100),
+		func(ctx context.Context, i int) (int, error) {
+			switch {
+			case i < 5:
+				return i, nil
+			case i == 5:
+				return 0, errFailed
+			}
+			<-ctx.Done()
+			atomic.AddInt32(&cancelled, 1)
+			return 0, ctx.Err()
+		},
+		func(out int) error {
+			got = append(got, out)
+			return nil
+		},
+	)
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("got error %v, want %v", err, errFailed)
+	}
+	if len(got) != 5 {
+		t.Errorf("got %d results, want the 5 before the failing input", len(got))
+	}
+	if cancelled > 3 {
+		t.Errorf("%d calls were started after the failure, want at most 3", cancelled)
+	}
+}
+
+func TestMapOrderedStopsOnEmitError(t *testing.T) {
+	const concurrency = 4
+	errEmit := errors.New("emit failed")
+	var calls int32
+
+	err := MapOrdered(context.Background(), concurrency, inputs(1000),
+		func(ctx context.Context, i int) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			return i, nil
+		},
+		func(out int) error {
+			return errEmit
+		},
+	)
+	if !errors.Is(err, errEmit) {
+		t.Fatalf("got error %v, want %v", err, errEmit)
+	}
+	// the failed emit never releases its window slot, so no call beyond the first window is dispatched
+	if calls > concurrency {
+		t.Errorf("%d calls were made, want at most %d", calls, concurrency)
+	}
+}
+
+func TestMapOrderedNoInputs(t *testing.T) {
+	err := MapOrdered(context.Background(), 0, nil,
+		func(ctx context.Context, i int) (int, error) {
+			t.Error("fn called without inputs")
+			return i, nil
+		},
+		func(out int) error {
+			t.Error("emit called without inputs")
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}